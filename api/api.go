@@ -1,32 +1,99 @@
 package api
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/klasrak/go-meli-test-dojo/config"
 )
 
 type Api struct {
-	Server http.Server
+	Server          http.Server
+	ReadinessDrain  time.Duration
+	ShutdownTimeout time.Duration
 }
 
+// Run serves until the process receives SIGINT or SIGTERM, then shuts the
+// server down gracefully. A second signal during the shutdown kills the
+// process right away.
 func (s *Api) Run() error {
-	if err := s.Server.ListenAndServe(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	return s.RunContext(ctx)
+}
+
+// RunContext serves until ctx is done, then reports not ready and keeps
+// serving for ReadinessDrain so that load balancers stop sending traffic.
+// After that it stops accepting connections and waits up to ShutdownTimeout
+// for in-flight requests to finish. Connections still open after that are
+// closed and the timeout error is returned.
+func (s *Api) RunContext(ctx context.Context) error {
+	serveErr := make(chan error, 1)
+
+	go func() {
+		serveErr <- s.Server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if err == http.ErrServerClosed {
+			return nil
+		}
+
+		return err
+	case <-ctx.Done():
+	}
+
+	setShuttingDown(true)
+	time.Sleep(s.ReadinessDrain)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.ShutdownTimeout)
+	defer cancel()
+
+	if err := s.Server.Shutdown(shutdownCtx); err != nil {
+		s.Server.Close()
 		return err
 	}
 
 	return nil
 }
 
-func New() *Api {
+// New builds the server described by cfg, logging requests to logOutput.
+func New(cfg config.Server, logOutput io.Writer) *Api {
 	router := chi.NewRouter()
+	middlewares := []func(http.Handler) http.Handler{}
+
+	if cfg.TrustProxy {
+		middlewares = append(middlewares, ProxyHeaders)
+	}
+
+	if cfg.RateLimit.Burst > 0 {
+		middlewares = append(middlewares, RateLimit(NewRateLimiter(cfg.RateLimit)))
+	}
 
-	URLMapping(router)
+	URLMapping(router, logOutput, middlewares...)
 
 	return &Api{
 		Server: http.Server{
-			Addr:    ":3000",
-			Handler: router,
+			Addr:         cfg.Addr,
+			Handler:      router,
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			IdleTimeout:  cfg.IdleTimeout,
 		},
+		ReadinessDrain:  cfg.ReadinessDrain,
+		ShutdownTimeout: cfg.ShutdownTimeout,
 	}
 }
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestApi returns an Api listening on a free local port whose only route
+// blocks until release is closed.
+func newTestApi(t *testing.T, release chan struct{}) (*Api, string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := listener.Addr().String()
+	listener.Close()
+
+	api := &Api{
+		Server: http.Server{
+			Addr: addr,
+			Handler: http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+				<-release
+				rw.WriteHeader(http.StatusOK)
+			}),
+		},
+		ShutdownTimeout: time.Second,
+	}
+
+	return api, "http://" + addr
+}
+
+func waitForServer(t *testing.T, url string) {
+	for i := 0; i < 100; i++ {
+		if conn, err := net.Dial("tcp", url[len("http://"):]); err == nil {
+			conn.Close()
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatal("server did not start")
+}
+
+func TestRunContextDrainsInFlightRequests(t *testing.T) {
+	defer setShuttingDown(false)
+
+	release := make(chan struct{})
+	api, url := newTestApi(t, release)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- api.RunContext(ctx)
+	}()
+
+	waitForServer(t, url)
+
+	statusCode := make(chan int, 1)
+
+	go func() {
+		res, err := http.Get(url)
+
+		if err != nil {
+			statusCode <- 0
+			return
+		}
+
+		res.Body.Close()
+		statusCode <- res.StatusCode
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+
+	if atomic.LoadInt32(&shuttingDown) != 1 {
+		t.Errorf("Assertion error. Expected readiness to be off while draining")
+	}
+
+	close(release)
+
+	if code := <-statusCode; code != http.StatusOK {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", http.StatusOK, code)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("Assertion error. Expected: nil, Got: %v", err)
+	}
+}
+
+func TestRunContextShutdownTimeout(t *testing.T) {
+	defer setShuttingDown(false)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	api, url := newTestApi(t, release)
+	api.ShutdownTimeout = 50 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- api.RunContext(ctx)
+	}()
+
+	waitForServer(t, url)
+
+	go http.Get(url)
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	if err := <-done; err != context.DeadlineExceeded {
+		t.Errorf("Assertion error. Expected: %v, Got: %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestRunContextReportsNotReadyBeforeShutdown(t *testing.T) {
+	defer setShuttingDown(false)
+
+	api, url := newTestApi(t, nil)
+	api.Server.Handler = http.HandlerFunc(ReadyHandler)
+	api.ReadinessDrain = 200 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+
+	go func() {
+		done <- api.RunContext(ctx)
+	}()
+
+	waitForServer(t, url)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+
+	res, err := http.Get(url)
+
+	if err != nil {
+		t.Fatalf("Assertion error. Expected the server to serve while draining, Got: %v", err)
+	}
+
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", http.StatusServiceUnavailable, res.StatusCode)
+	}
+
+	if err := <-done; err != nil {
+		t.Errorf("Assertion error. Expected: nil, Got: %v", err)
+	}
+}
+
+func TestRunContextListenError(t *testing.T) {
+	api := &Api{Server: http.Server{Addr: "invalid-address"}, ShutdownTimeout: time.Second}
+
+	if err := api.RunContext(context.Background()); err == nil {
+		t.Errorf("Assertion error. Expected an error, Got: nil")
+	}
+}
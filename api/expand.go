@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/klasrak/go-meli-test-dojo/services"
+	"github.com/klasrak/go-meli-test-dojo/utils"
+)
+
+// expandable lists, per resource, the fields holding links to other
+// resources that can be expanded with ?expand=.
+var expandable = map[string][]string{
+	"starships": {"films", "pilots"},
+	"people":    {"homeworld", "films", "species", "starships", "vehicles"},
+	"films":     {"characters", "planets", "starships", "vehicles", "species"},
+	"planets":   {"residents", "films"},
+	"species":   {"homeworld", "people", "films"},
+	"vehicles":  {"films", "pilots"},
+}
+
+// expand replaces the links in fields of every item with the resources they
+// point to. Links that cannot be resolved are dropped from the field and
+// listed under "unresolved" instead of failing the response; the key is left
+// out when every link was resolved.
+func expand(ctx context.Context, items []*utils.Object, fields []string) error {
+	links := []string{}
+
+	for _, item := range items {
+		for _, field := range fields {
+			many, one := itemLinks(item, field)
+			links = append(links, many...)
+
+			if one != "" {
+				links = append(links, one)
+			}
+		}
+	}
+
+	resolved := services.ResolveLinksService(ctx, links)
+
+	for _, item := range items {
+		unresolved := []string{}
+
+		for _, field := range fields {
+			many, one := itemLinks(item, field)
+
+			if many != nil {
+				expanded := []interface{}{}
+
+				for _, link := range many {
+					if value, ok := resolved[link]; ok {
+						expanded = append(expanded, value)
+					} else {
+						unresolved = append(unresolved, link)
+					}
+				}
+
+				if err := item.Set(field, expanded); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			value, ok := resolved[one]
+
+			if !ok && one != "" {
+				unresolved = append(unresolved, one)
+			}
+
+			if err := item.Set(field, value); err != nil {
+				return err
+			}
+		}
+
+		if len(unresolved) == 0 {
+			continue
+		}
+
+		if err := item.Set("unresolved", unresolved); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// itemLinks reads field of item either as a list of links or as a single
+// link.
+func itemLinks(item *utils.Object, field string) ([]string, string) {
+	raw, ok := item.Get(field)
+
+	if !ok {
+		return nil, ""
+	}
+
+	var many []string
+
+	if err := json.Unmarshal(raw, &many); err == nil {
+		if many == nil {
+			many = []string{}
+		}
+
+		return many, ""
+	}
+
+	var one string
+	json.Unmarshal(raw, &one)
+
+	return nil, one
+}
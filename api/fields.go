@@ -0,0 +1,58 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/klasrak/go-meli-test-dojo/models"
+	"github.com/klasrak/go-meli-test-dojo/utils"
+)
+
+// selectable lists, per resource, the fields that can be picked with
+// ?fields=, taken from the JSON tags of its model.
+var selectable = map[string][]string{
+	"starships": jsonFields(models.Starship{}),
+	"people":    jsonFields(models.People{}),
+	"films":     jsonFields(models.Film{}),
+	"planets":   jsonFields(models.Planet{}),
+	"species":   jsonFields(models.Species{}),
+	"vehicles":  jsonFields(models.Vehicle{}),
+}
+
+// jsonFields returns the names v is encoded with, in declaration order.
+func jsonFields(v interface{}) []string {
+	fields := []string{}
+	t := reflect.TypeOf(v)
+
+	for i := 0; i < t.NumField(); i++ {
+		if name := jsonName(t.Field(i)); name != "" {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// jsonName is the name field is encoded with, or "" when it is not encoded.
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+	if name == "-" {
+		return ""
+	}
+
+	return name
+}
+
+// selectFields drops from every item the fields not listed in fields. The
+// "unresolved" field added by expand is kept, since render only expands the
+// selected fields.
+func selectFields(items []*utils.Object, fields []string) {
+	for _, item := range items {
+		for _, key := range append([]string{}, item.Keys()...) {
+			if key != "unresolved" && len(unknownFields([]string{key}, fields)) > 0 {
+				item.Delete(key)
+			}
+		}
+	}
+}
@@ -1,8 +1,11 @@
 package api
 
 import (
+	"fmt"
 	"net/http"
 	"strconv"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/klasrak/go-meli-test-dojo/errors"
 	"github.com/klasrak/go-meli-test-dojo/httphelpers"
@@ -11,6 +14,8 @@ import (
 	"github.com/go-chi/chi/v5"
 )
 
+const maxSearchLength = 100
+
 func GetStarshipHandler(rw http.ResponseWriter, r *http.Request) {
 	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 
@@ -19,35 +24,39 @@ func GetStarshipHandler(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := services.GetStarshipService(id)
+	result, err := services.GetStarshipService(r.Context(), id)
 
 	if err != nil {
-		if errors.Status(err) == http.StatusNotFound {
-			httphelpers.NotFound(rw, err)
-			return
-		} else {
-			httphelpers.InternalServerError(rw)
-			return
-		}
+		httphelpers.Error(rw, err)
+		return
 	}
 
-	httphelpers.OK(rw, result)
+	respond(rw, r, result)
 }
 
 func GetStarshipsHandler(rw http.ResponseWriter, r *http.Request) {
-	result, err := services.GetStarshipsService()
+	page, err := pageParam(r)
 
 	if err != nil {
-		if errors.Status(err) == http.StatusNotFound {
-			httphelpers.NotFound(rw, err)
-			return
-		} else {
-			httphelpers.InternalServerError(rw)
-			return
-		}
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	search, err := searchParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
 	}
 
-	httphelpers.OK(rw, result)
+	result, err := services.GetStarshipsService(r.Context(), page, search)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
 }
 
 func GetPeopleHandler(rw http.ResponseWriter, r *http.Request) {
@@ -58,33 +67,263 @@ func GetPeopleHandler(rw http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result, err := services.GetPeopleService(id)
+	result, err := services.GetPeopleService(r.Context(), id)
 
 	if err != nil {
-		if errors.Status(err) == http.StatusNotFound {
-			httphelpers.NotFound(rw, err)
-			return
-		} else {
-			httphelpers.InternalServerError(rw)
-			return
-		}
+		httphelpers.Error(rw, err)
+		return
 	}
 
-	httphelpers.OK(rw, result)
+	respond(rw, r, result)
 }
 
 func GetPeopleListHandler(rw http.ResponseWriter, r *http.Request) {
-	result, err := services.GetPeopleListService()
+	page, err := pageParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	search, err := searchParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	result, err := services.GetPeopleListService(r.Context(), page, search)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
+}
+
+func GetFilmHandler(rw http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
 
 	if err != nil {
-		if errors.Status(err) == http.StatusNotFound {
-			httphelpers.NotFound(rw, err)
-			return
-		} else {
-			httphelpers.InternalServerError(rw)
-			return
+		httphelpers.BadRequest(rw, errors.NewBadRequest("invalid id"))
+		return
+	}
+
+	result, err := services.GetFilmService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
+}
+
+func GetFilmsHandler(rw http.ResponseWriter, r *http.Request) {
+	page, err := pageParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	search, err := searchParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	result, err := services.GetFilmsService(r.Context(), page, search)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
+}
+
+func GetPlanetHandler(rw http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		httphelpers.BadRequest(rw, errors.NewBadRequest("invalid id"))
+		return
+	}
+
+	result, err := services.GetPlanetService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
+}
+
+func GetPlanetsHandler(rw http.ResponseWriter, r *http.Request) {
+	page, err := pageParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	search, err := searchParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	result, err := services.GetPlanetsService(r.Context(), page, search)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
+}
+
+func GetPeopleHomeworldHandler(rw http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		httphelpers.BadRequest(rw, errors.NewBadRequest("invalid id"))
+		return
+	}
+
+	result, err := services.GetPeopleHomeworldService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
+}
+
+func GetSpeciesHandler(rw http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		httphelpers.BadRequest(rw, errors.NewBadRequest("invalid id"))
+		return
+	}
+
+	result, err := services.GetSpeciesService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
+}
+
+func GetSpeciesListHandler(rw http.ResponseWriter, r *http.Request) {
+	page, err := pageParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	search, err := searchParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	result, err := services.GetSpeciesListService(r.Context(), page, search)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
+}
+
+func GetVehicleHandler(rw http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+
+	if err != nil {
+		httphelpers.BadRequest(rw, errors.NewBadRequest("invalid id"))
+		return
+	}
+
+	result, err := services.GetVehicleService(r.Context(), id)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
+}
+
+func GetVehiclesHandler(rw http.ResponseWriter, r *http.Request) {
+	page, err := pageParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	search, err := searchParam(r)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	result, err := services.GetVehiclesService(r.Context(), page, search)
+
+	if err != nil {
+		httphelpers.Error(rw, err)
+		return
+	}
+
+	respond(rw, r, result)
+}
+
+// pageParam reads the optional "page" query parameter. A missing page
+// yields 0, which asks for the full collection.
+func pageParam(r *http.Request) (int, error) {
+	value := r.URL.Query().Get("page")
+
+	if value == "" {
+		return 0, nil
+	}
+
+	page, err := strconv.Atoi(value)
+
+	if err != nil || page < 1 {
+		return 0, errors.NewBadRequest(fmt.Sprintf("invalid page: %s", value))
+	}
+
+	return page, nil
+}
+
+// searchParam reads the optional "search" query parameter that is forwarded
+// to the upstream API.
+func searchParam(r *http.Request) (string, error) {
+	search := r.URL.Query().Get("search")
+
+	if utf8.RuneCountInString(search) > maxSearchLength {
+		return "", errors.NewBadRequest(fmt.Sprintf("search must have at most %d characters", maxSearchLength))
+	}
+
+	for _, char := range search {
+		if char == utf8.RuneError || unicode.IsControl(char) {
+			return "", errors.NewBadRequest("search contains invalid characters")
 		}
 	}
 
-	httphelpers.OK(rw, result)
+	return search, nil
 }
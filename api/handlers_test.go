@@ -1,12 +1,15 @@
 package api
 
 import (
+	"context"
 	"github.com/klasrak/go-meli-test-dojo/clients/swapi"
 	"github.com/klasrak/go-meli-test-dojo/errors"
 	"github.com/klasrak/go-meli-test-dojo/mockeable"
 	"github.com/klasrak/go-meli-test-dojo/models"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestGetStarshipsHandlerBadRequest(t *testing.T) {
@@ -25,7 +28,7 @@ func TestGetStarshipHandlerSuccess(t *testing.T) {
 	url := "/api/v1/starships/9"
 
 	mock := swapi.MockClient{
-		GetStarshipFunc: func(id int) (models.Starship, error) {
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
 			if id != 9 {
 				t.Errorf("Assertion error. Expected: %d, Got: %d", 9, id)
 			}
@@ -57,7 +60,7 @@ func TestGetStarshipHandlerSuccess(t *testing.T) {
 
 	response := DoRequest(http.MethodGet, url, nil, "")
 	statusCodeExpected := 200
-	expectedBody := `{"name":"Death Star","model":"DS-1 Orbital Battle Station","starship_class":"Deep Space Mobile Battlestation","manufacturer":"Imperial Department of Military Research, Sienar Fleet Systems","cost_in_credits":"1000000000000","length":"120000","crew":"342953","passengers":"843342","max_atmosphering_speed":"n/a","hyperdrive_rating":"4.0","MGLT":"10","cargo_capacity":"1000000000000","consumables":"3 years","films":["https://swapi.dev/api/films/1/"],"pilots":null}`
+	expectedBody := `{"id":0,"name":"Death Star","model":"DS-1 Orbital Battle Station","starship_class":"Deep Space Mobile Battlestation","manufacturer":"Imperial Department of Military Research, Sienar Fleet Systems","cost_in_credits":"1000000000000","length":"120000","crew":"342953","passengers":"843342","max_atmosphering_speed":"n/a","hyperdrive_rating":"4.0","MGLT":"10","cargo_capacity":"1000000000000","consumables":"3 years","films":["http://example.com/api/v1/films/1"],"pilots":null,"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""}`
 
 	if response.StatusCode != statusCodeExpected {
 		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
@@ -73,7 +76,7 @@ func TestGetStarshipHandlerNotFound(t *testing.T) {
 	expectedError := 404
 
 	mock := swapi.MockClient{
-		GetStarshipFunc: func(id int) (models.Starship, error) {
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
 			if id != 9 {
 				t.Errorf("Assertion error. Expected: %d, Got: %d", 9, id)
 			}
@@ -98,7 +101,7 @@ func TestGetStarshipHandlerInternalServerError(t *testing.T) {
 	expectedError := 500
 
 	mock := swapi.MockClient{
-		GetStarshipFunc: func(id int) (models.Starship, error) {
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
 			if id != 9 {
 				t.Errorf("Assertion error. Expected: %d, Got: %d", 9, id)
 			}
@@ -122,7 +125,7 @@ func TestGetStarshipsHandlerInternalServerError(t *testing.T) {
 	expectedError := 500
 
 	mock := swapi.MockClient{
-		GetStarshipsFunc: func() (models.Starships, error) {
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
 			return models.Starships{}, errors.NewInternal()
 		},
 		GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
@@ -143,7 +146,7 @@ func TestGetStarshipsHandlerNotFound(t *testing.T) {
 	expectedError := 404
 
 	mock := swapi.MockClient{
-		GetStarshipsFunc: func() (models.Starships, error) {
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
 			return models.Starships{}, errors.NewNotFound("Not found", "starships not found")
 		},
 		GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
@@ -163,7 +166,7 @@ func TestGetStarshipsHandlerSuccess(t *testing.T) {
 	url := "/api/v1/starships"
 
 	mock := swapi.MockClient{
-		GetStarshipsFunc: func() (models.Starships, error) {
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
 			return models.Starships{
 				Count: 2,
 				Results: []models.Starship{
@@ -218,7 +221,7 @@ func TestGetStarshipsHandlerSuccess(t *testing.T) {
 
 	response := DoRequest(http.MethodGet, url, nil, "")
 	statusCodeExpected := 200
-	expectedBody := `{"count":2,"results":[{"name":"CR90 corvette","model":"CR90 corvette","starship_class":"1 year","manufacturer":"corvette","cost_in_credits":"Corellian Engineering Corporation","length":"3500000","crew":"30-165","passengers":"600","max_atmosphering_speed":"150","hyperdrive_rating":"60","MGLT":"3000000","cargo_capacity":"950","consumables":"2.0","films":["https://swapi.dev/api/films/1/","https://swapi.dev/api/films/3/","https://swapi.dev/api/films/6/"],"pilots":[]},{"name":"Star Destroyer","model":"Imperial I-class Star Destroyer","starship_class":"Star Destroyer","manufacturer":"Kuat Drive Yards","cost_in_credits":"150000000","length":"1,600","crew":"47,060","passengers":"n/a","max_atmosphering_speed":"975","hyperdrive_rating":"2.0","MGLT":"60","cargo_capacity":"36000000","consumables":"2 years","films":["https://swapi.dev/api/films/1/","https://swapi.dev/api/films/2/","https://swapi.dev/api/films/3/"],"pilots":[]}]}`
+	expectedBody := `{"count":2,"next":null,"previous":null,"results":[{"id":0,"name":"CR90 corvette","model":"CR90 corvette","starship_class":"1 year","manufacturer":"corvette","cost_in_credits":"Corellian Engineering Corporation","length":"3500000","crew":"30-165","passengers":"600","max_atmosphering_speed":"150","hyperdrive_rating":"60","MGLT":"3000000","cargo_capacity":"950","consumables":"2.0","films":["http://example.com/api/v1/films/1","http://example.com/api/v1/films/3","http://example.com/api/v1/films/6"],"pilots":[],"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""},{"id":0,"name":"Star Destroyer","model":"Imperial I-class Star Destroyer","starship_class":"Star Destroyer","manufacturer":"Kuat Drive Yards","cost_in_credits":"150000000","length":"1,600","crew":"47,060","passengers":"n/a","max_atmosphering_speed":"975","hyperdrive_rating":"2.0","MGLT":"60","cargo_capacity":"36000000","consumables":"2 years","films":["http://example.com/api/v1/films/1","http://example.com/api/v1/films/2","http://example.com/api/v1/films/3"],"pilots":[],"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""}]}`
 
 	if response.StatusCode != statusCodeExpected {
 		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
@@ -244,7 +247,7 @@ func TestGetPeopleHandlerSuccess(t *testing.T) {
 
 	url := "/api/v1/people/1"
 
-	mock := swapi.MockClient{GetPeopleFunc: func(id int) (models.People, error) {
+	mock := swapi.MockClient{GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
 		if id != 1 {
 			t.Errorf("Assertion error. Expected: %d, Got: %d", 1, id)
 		}
@@ -278,7 +281,7 @@ func TestGetPeopleHandlerSuccess(t *testing.T) {
 
 	response := DoRequest(http.MethodGet, url, nil, "")
 	statusCodeExpected := 200
-	expectedBody := `{"name":"Luke Skywalker","birth_year":"19BBY","eye_color":"blue","gender":"male","hair_color":"blond","height":"172","mass":"77","skin_color":"fair","homeworld":"https://swapi.dev/api/planets/1/","films":["https://swapi.dev/api/films/1/","https://swapi.dev/api/films/2/","https://swapi.dev/api/films/3/","https://swapi.dev/api/films/6/"],"species":[],"starships":["https://swapi.dev/api/starships/12/","https://swapi.dev/api/starships/22/"]}`
+	expectedBody := `{"id":0,"name":"Luke Skywalker","birth_year":"19BBY","eye_color":"blue","gender":"male","hair_color":"blond","height":"172","mass":"77","skin_color":"fair","homeworld":"http://example.com/api/v1/planets/1","films":["http://example.com/api/v1/films/1","http://example.com/api/v1/films/2","http://example.com/api/v1/films/3","http://example.com/api/v1/films/6"],"species":[],"starships":["http://example.com/api/v1/starships/12","http://example.com/api/v1/starships/22"],"vehicles":null,"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""}`
 
 	if response.StatusCode != statusCodeExpected {
 		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
@@ -294,7 +297,7 @@ func TestGetPeopleHandlerNotFound(t *testing.T) {
 	url := "/api/v1/people/1"
 	expectedError := 404
 
-	mock := swapi.MockClient{GetPeopleFunc: func(id int) (models.People, error) {
+	mock := swapi.MockClient{GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
 		if id != 1 {
 			t.Errorf("Assertion error. Expected: %d, Got: %d", 1, id)
 		}
@@ -318,7 +321,7 @@ func TestGetPeopleHandlerInternalServerError(t *testing.T) {
 	url := "/api/v1/people/1"
 	expectedError := 500
 
-	mock := swapi.MockClient{GetPeopleFunc: func(id int) (models.People, error) {
+	mock := swapi.MockClient{GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
 		if id != 1 {
 			t.Errorf("Assertion error. Expected: %d, Got: %d", 1, id)
 		}
@@ -342,7 +345,7 @@ func TestGetPeopleListHandlerNotFound(t *testing.T) {
 	expectedError := 404
 
 	mock := swapi.MockClient{
-		GetPeopleListFunc: func() (models.PeopleList, error) {
+		GetPeopleListFunc: func(ctx context.Context, page int, search string) (models.PeopleList, error) {
 			return models.PeopleList{}, errors.NewNotFound("Not found", "peoples not found")
 		},
 		GetPeopleListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
@@ -363,7 +366,7 @@ func TestGetPeopleListHandlerInternalServerError(t *testing.T) {
 	expectedError := 500
 
 	mock := swapi.MockClient{
-		GetPeopleListFunc: func() (models.PeopleList, error) {
+		GetPeopleListFunc: func(ctx context.Context, page int, search string) (models.PeopleList, error) {
 			return models.PeopleList{}, errors.NewInternal()
 		},
 		GetPeopleListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
@@ -383,7 +386,7 @@ func TestGetPeopleListHandlerSuccess(t *testing.T) {
 	url := "/api/v1/people"
 
 	mock := swapi.MockClient{
-		GetPeopleListFunc: func() (models.PeopleList, error) {
+		GetPeopleListFunc: func(ctx context.Context, page int, search string) (models.PeopleList, error) {
 			return models.PeopleList{
 				Count: 2,
 				Results: []models.People{
@@ -443,7 +446,1552 @@ func TestGetPeopleListHandlerSuccess(t *testing.T) {
 
 	response := DoRequest(http.MethodGet, url, nil, "")
 	statusCodeExpected := 200
-	expectedBody := `{"count":2,"results":[{"name":"Luke Skywalker","birth_year":"19BBY","eye_color":"blue","gender":"male","hair_color":"blond","height":"172","mass":"77","skin_color":"fair","homeworld":"https://swapi.dev/api/planets/1/","films":["https://swapi.dev/api/films/1/","https://swapi.dev/api/films/2/","https://swapi.dev/api/films/3/","https://swapi.dev/api/films/6/"],"species":[],"starships":["https://swapi.dev/api/starships/12/","https://swapi.dev/api/starships/22/"]},{"name":"C-3PO","birth_year":"112BBY","eye_color":"yellow","gender":"n/a","hair_color":"n/a","height":"167","mass":"75","skin_color":"gold","homeworld":"https://swapi.dev/api/planets/1/","films":["https://swapi.dev/api/films/1/","https://swapi.dev/api/films/2/","https://swapi.dev/api/films/3/","https://swapi.dev/api/films/4/","https://swapi.dev/api/films/5/","https://swapi.dev/api/films/6/"],"species":["https://swapi.dev/api/species/2/"],"starships":[]}]}`
+	expectedBody := `{"count":2,"next":null,"previous":null,"results":[{"id":0,"name":"Luke Skywalker","birth_year":"19BBY","eye_color":"blue","gender":"male","hair_color":"blond","height":"172","mass":"77","skin_color":"fair","homeworld":"http://example.com/api/v1/planets/1","films":["http://example.com/api/v1/films/1","http://example.com/api/v1/films/2","http://example.com/api/v1/films/3","http://example.com/api/v1/films/6"],"species":[],"starships":["http://example.com/api/v1/starships/12","http://example.com/api/v1/starships/22"],"vehicles":null,"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""},{"id":0,"name":"C-3PO","birth_year":"112BBY","eye_color":"yellow","gender":"n/a","hair_color":"n/a","height":"167","mass":"75","skin_color":"gold","homeworld":"http://example.com/api/v1/planets/1","films":["http://example.com/api/v1/films/1","http://example.com/api/v1/films/2","http://example.com/api/v1/films/3","http://example.com/api/v1/films/4","http://example.com/api/v1/films/5","http://example.com/api/v1/films/6"],"species":["http://example.com/api/v1/species/2"],"starships":[],"vehicles":null,"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetFilmHandlerBadRequest(t *testing.T) {
+
+	url := "/api/v1/films/invalid_id"
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 400
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestGetFilmHandlerSuccess(t *testing.T) {
+
+	url := "/api/v1/films/1"
+
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			if id != 1 {
+				t.Errorf("Assertion error. Expected: %d, Got: %d", 1, id)
+			}
+
+			return models.Film{
+				Title:        "A New Hope",
+				EpisodeID:    4,
+				OpeningCrawl: "It is a period of civil war.",
+				Director:     "George Lucas",
+				Producer:     "Gary Kurtz, Rick McCallum",
+				ReleaseDate:  "1977-05-25",
+				Characters: []string{
+					"https://swapi.dev/api/people/1/",
+				},
+				Planets: []string{
+					"https://swapi.dev/api/planets/1/",
+				},
+				Starships: []string{
+					"https://swapi.dev/api/starships/9/",
+				},
+				Vehicles: []string{},
+				Species:  []string{},
+			}, nil
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"title":"A New Hope","episode_id":4,"opening_crawl":"It is a period of civil war.","director":"George Lucas","producer":"Gary Kurtz, Rick McCallum","release_date":"1977-05-25","characters":["http://example.com/api/v1/people/1"],"planets":["http://example.com/api/v1/planets/1"],"starships":["http://example.com/api/v1/starships/9"],"vehicles":[],"species":[]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetFilmHandlerNotFound(t *testing.T) {
+	url := "/api/v1/films/7"
+	expectedError := 404
+
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			if id != 7 {
+				t.Errorf("Assertion error. Expected: %d, Got: %d", 7, id)
+			}
+
+			return models.Film{}, errors.NewNotFound("films", "7")
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetFilmHandlerInternalServerError(t *testing.T) {
+	url := "/api/v1/films/1"
+	expectedError := 500
+
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			return models.Film{}, errors.NewInternal()
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetFilmsHandlerNotFound(t *testing.T) {
+	url := "/api/v1/films"
+	expectedError := 404
+
+	mock := swapi.MockClient{
+		GetFilmsFunc: func(ctx context.Context, page int, search string) (models.Films, error) {
+			return models.Films{}, errors.NewNotFound("films", "")
+		},
+		GetFilmsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetFilmsHandlerInternalServerError(t *testing.T) {
+	url := "/api/v1/films"
+	expectedError := 500
+
+	mock := swapi.MockClient{
+		GetFilmsFunc: func(ctx context.Context, page int, search string) (models.Films, error) {
+			return models.Films{}, errors.NewInternal()
+		},
+		GetFilmsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetFilmsHandlerSuccess(t *testing.T) {
+	url := "/api/v1/films"
+
+	mock := swapi.MockClient{
+		GetFilmsFunc: func(ctx context.Context, page int, search string) (models.Films, error) {
+			return models.Films{
+				Count: 1,
+				Results: []models.Film{
+					{
+						Title:       "The Empire Strikes Back",
+						EpisodeID:   5,
+						Director:    "Irvin Kershner",
+						Producer:    "Gary Kurtz, Rick McCallum",
+						ReleaseDate: "1980-05-17",
+						Characters:  []string{},
+						Planets:     []string{},
+						Starships:   []string{},
+						Vehicles:    []string{},
+						Species:     []string{},
+					},
+				},
+			}, nil
+		},
+		GetFilmsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"count":1,"next":null,"previous":null,"results":[{"title":"The Empire Strikes Back","episode_id":5,"opening_crawl":"","director":"Irvin Kershner","producer":"Gary Kurtz, Rick McCallum","release_date":"1980-05-17","characters":[],"planets":[],"starships":[],"vehicles":[],"species":[]}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetPlanetHandlerBadRequest(t *testing.T) {
+
+	url := "/api/v1/planets/invalid_id"
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 400
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestGetPlanetHandlerSuccess(t *testing.T) {
+
+	url := "/api/v1/planets/1"
+
+	mock := swapi.MockClient{
+		GetPlanetFunc: func(ctx context.Context, id int) (models.Planet, error) {
+			if id != 1 {
+				t.Errorf("Assertion error. Expected: %d, Got: %d", 1, id)
+			}
+
+			return models.Planet{
+				Name:           "Tatooine",
+				RotationPeriod: "23",
+				OrbitalPeriod:  "304",
+				Diameter:       "10465",
+				Climate:        "arid",
+				Gravity:        "1 standard",
+				Terrain:        "desert",
+				SurfaceWater:   "1",
+				Population:     "200000",
+				Residents: []string{
+					"https://swapi.dev/api/people/1/",
+				},
+				Films: []string{
+					"https://swapi.dev/api/films/1/",
+				},
+			}, nil
+		},
+		GetPlanetFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"name":"Tatooine","rotation_period":"23","orbital_period":"304","diameter":"10465","climate":"arid","gravity":"1 standard","terrain":"desert","surface_water":"1","population":"200000","residents":["http://example.com/api/v1/people/1"],"films":["http://example.com/api/v1/films/1"]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetPlanetHandlerNotFound(t *testing.T) {
+	url := "/api/v1/planets/99"
+	expectedError := 404
+
+	mock := swapi.MockClient{
+		GetPlanetFunc: func(ctx context.Context, id int) (models.Planet, error) {
+			return models.Planet{}, errors.NewNotFound("planets", "99")
+		},
+		GetPlanetFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetPlanetsHandlerInternalServerError(t *testing.T) {
+	url := "/api/v1/planets"
+	expectedError := 500
+
+	mock := swapi.MockClient{
+		GetPlanetsFunc: func(ctx context.Context, page int, search string) (models.Planets, error) {
+			return models.Planets{}, errors.NewInternal()
+		},
+		GetPlanetsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetPlanetsHandlerSuccess(t *testing.T) {
+	url := "/api/v1/planets"
+
+	mock := swapi.MockClient{
+		GetPlanetsFunc: func(ctx context.Context, page int, search string) (models.Planets, error) {
+			return models.Planets{
+				Count: 1,
+				Results: []models.Planet{
+					{
+						Name:           "Alderaan",
+						RotationPeriod: "24",
+						OrbitalPeriod:  "364",
+						Diameter:       "12500",
+						Climate:        "temperate",
+						Gravity:        "1 standard",
+						Terrain:        "grasslands, mountains",
+						SurfaceWater:   "40",
+						Population:     "2000000000",
+						Residents:      []string{},
+						Films:          []string{},
+					},
+				},
+			}, nil
+		},
+		GetPlanetsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"count":1,"next":null,"previous":null,"results":[{"name":"Alderaan","rotation_period":"24","orbital_period":"364","diameter":"12500","climate":"temperate","gravity":"1 standard","terrain":"grasslands, mountains","surface_water":"40","population":"2000000000","residents":[],"films":[]}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetPeopleHomeworldHandlerSuccess(t *testing.T) {
+	url := "/api/v1/people/1/homeworld"
+
+	mock := swapi.MockClient{
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			if id != 1 {
+				t.Errorf("Assertion error. Expected: %d, Got: %d", 1, id)
+			}
+
+			return models.People{
+				Name:      "Luke Skywalker",
+				Homeworld: "https://swapi.dev/api/planets/1/",
+			}, nil
+		},
+		GetPlanetFunc: func(ctx context.Context, id int) (models.Planet, error) {
+			if id != 1 {
+				t.Errorf("Assertion error. Expected: %d, Got: %d", 1, id)
+			}
+
+			return models.Planet{Name: "Tatooine"}, nil
+		},
+		GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		GetPlanetFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"name":"Tatooine","rotation_period":"","orbital_period":"","diameter":"","climate":"","gravity":"","terrain":"","surface_water":"","population":"","residents":null,"films":null}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetPeopleHomeworldHandlerNotFound(t *testing.T) {
+	url := "/api/v1/people/1/homeworld"
+	expectedError := 404
+
+	mock := swapi.MockClient{
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			return models.People{Name: "Luke Skywalker"}, nil
+		},
+		GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		GetPlanetFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 0},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetSpeciesHandlerBadRequest(t *testing.T) {
+
+	url := "/api/v1/species/invalid_id"
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 400
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestGetSpeciesHandlerSuccess(t *testing.T) {
+
+	url := "/api/v1/species/2"
+
+	mock := swapi.MockClient{
+		GetSpeciesFunc: func(ctx context.Context, id int) (models.Species, error) {
+			if id != 2 {
+				t.Errorf("Assertion error. Expected: %d, Got: %d", 2, id)
+			}
+
+			return models.Species{
+				Name:            "Droid",
+				Classification:  "artificial",
+				Designation:     "sentient",
+				AverageHeight:   "n/a",
+				AverageLifespan: "indefinite",
+				EyeColors:       "n/a",
+				HairColors:      "n/a",
+				SkinColors:      "n/a",
+				Language:        "n/a",
+				People: []string{
+					"https://swapi.dev/api/people/2/",
+				},
+				Films: []string{
+					"https://swapi.dev/api/films/1/",
+				},
+			}, nil
+		},
+		GetSpeciesFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"name":"Droid","classification":"artificial","designation":"sentient","average_height":"n/a","average_lifespan":"indefinite","eye_colors":"n/a","hair_colors":"n/a","skin_colors":"n/a","language":"n/a","homeworld":"","people":["http://example.com/api/v1/people/2"],"films":["http://example.com/api/v1/films/1"]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetSpeciesHandlerNotFound(t *testing.T) {
+	url := "/api/v1/species/99"
+	expectedError := 404
+
+	mock := swapi.MockClient{
+		GetSpeciesFunc: func(ctx context.Context, id int) (models.Species, error) {
+			return models.Species{}, errors.NewNotFound("species", "99")
+		},
+		GetSpeciesFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetSpeciesListHandlerInternalServerError(t *testing.T) {
+	url := "/api/v1/species"
+	expectedError := 500
+
+	mock := swapi.MockClient{
+		GetSpeciesListFunc: func(ctx context.Context, page int, search string) (models.SpeciesList, error) {
+			return models.SpeciesList{}, errors.NewInternal()
+		},
+		GetSpeciesListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetSpeciesListHandlerSuccess(t *testing.T) {
+	url := "/api/v1/species"
+
+	mock := swapi.MockClient{
+		GetSpeciesListFunc: func(ctx context.Context, page int, search string) (models.SpeciesList, error) {
+			return models.SpeciesList{
+				Count: 1,
+				Results: []models.Species{
+					{
+						Name:            "Human",
+						Classification:  "mammal",
+						Designation:     "sentient",
+						AverageHeight:   "180",
+						AverageLifespan: "120",
+						EyeColors:       "brown, blue, green, hazel, grey, amber",
+						HairColors:      "blonde, brown, black, red",
+						SkinColors:      "caucasian, black, asian, hispanic",
+						Language:        "Galactic Basic",
+						Homeworld:       "https://swapi.dev/api/planets/9/",
+						People:          []string{},
+						Films:           []string{},
+					},
+				},
+			}, nil
+		},
+		GetSpeciesListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"count":1,"next":null,"previous":null,"results":[{"name":"Human","classification":"mammal","designation":"sentient","average_height":"180","average_lifespan":"120","eye_colors":"brown, blue, green, hazel, grey, amber","hair_colors":"blonde, brown, black, red","skin_colors":"caucasian, black, asian, hispanic","language":"Galactic Basic","homeworld":"http://example.com/api/v1/planets/9","people":[],"films":[]}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetVehicleHandlerBadRequest(t *testing.T) {
+
+	url := "/api/v1/vehicles/invalid_id"
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 400
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestGetVehicleHandlerSuccess(t *testing.T) {
+
+	url := "/api/v1/vehicles/4"
+
+	mock := swapi.MockClient{
+		GetVehicleFunc: func(ctx context.Context, id int) (models.Vehicle, error) {
+			if id != 4 {
+				t.Errorf("Assertion error. Expected: %d, Got: %d", 4, id)
+			}
+
+			return models.Vehicle{
+				Name:                 "Sand Crawler",
+				Model:                "Digger Crawler",
+				Class:                "wheeled",
+				Manufacturer:         "Corellia Mining Corporation",
+				CostInCredits:        "150000",
+				Length:               "36.8 ",
+				Crew:                 "46",
+				Passengers:           "30",
+				MaxAtmospheringSpeed: "30",
+				CargoCapacity:        "50000",
+				Consumables:          "2 months",
+				Films: []string{
+					"https://swapi.dev/api/films/1/",
+				},
+				Pilots: []string{},
+			}, nil
+		},
+		GetVehicleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"name":"Sand Crawler","model":"Digger Crawler","vehicle_class":"wheeled","manufacturer":"Corellia Mining Corporation","cost_in_credits":"150000","length":"36.8 ","crew":"46","passengers":"30","max_atmosphering_speed":"30","cargo_capacity":"50000","consumables":"2 months","films":["http://example.com/api/v1/films/1"],"pilots":[]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetVehicleHandlerInternalServerError(t *testing.T) {
+	url := "/api/v1/vehicles/4"
+	expectedError := 500
+
+	mock := swapi.MockClient{
+		GetVehicleFunc: func(ctx context.Context, id int) (models.Vehicle, error) {
+			return models.Vehicle{}, errors.NewInternal()
+		},
+		GetVehicleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetVehiclesHandlerNotFound(t *testing.T) {
+	url := "/api/v1/vehicles"
+	expectedError := 404
+
+	mock := swapi.MockClient{
+		GetVehiclesFunc: func(ctx context.Context, page int, search string) (models.Vehicles, error) {
+			return models.Vehicles{}, errors.NewNotFound("vehicles", "")
+		},
+		GetVehiclesFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetVehiclesHandlerSuccess(t *testing.T) {
+	url := "/api/v1/vehicles"
+
+	mock := swapi.MockClient{
+		GetVehiclesFunc: func(ctx context.Context, page int, search string) (models.Vehicles, error) {
+			return models.Vehicles{
+				Count: 1,
+				Results: []models.Vehicle{
+					{
+						Name:                 "T-16 skyhopper",
+						Model:                "T-16 skyhopper",
+						Class:                "repulsorcraft",
+						Manufacturer:         "Incom Corporation",
+						CostInCredits:        "14500",
+						Length:               "10.4 ",
+						Crew:                 "1",
+						Passengers:           "1",
+						MaxAtmospheringSpeed: "1200",
+						CargoCapacity:        "50",
+						Consumables:          "0",
+						Films:                []string{},
+						Pilots:               []string{},
+					},
+				},
+			}, nil
+		},
+		GetVehiclesFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"count":1,"next":null,"previous":null,"results":[{"name":"T-16 skyhopper","model":"T-16 skyhopper","vehicle_class":"repulsorcraft","manufacturer":"Incom Corporation","cost_in_credits":"14500","length":"10.4 ","crew":"1","passengers":"1","max_atmosphering_speed":"1200","cargo_capacity":"50","consumables":"0","films":[],"pilots":[]}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetStarshipsHandlerPage(t *testing.T) {
+	url := "/api/v1/starships?page=2"
+
+	mock := swapi.MockClient{
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
+			if page != 2 {
+				t.Errorf("Assertion error. Expected: %d, Got: %d", 2, page)
+			}
+
+			return models.Starships{Count: 36, Results: []models.Starship{}}, nil
+		},
+		GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestGetPeopleListHandlerInvalidPage(t *testing.T) {
+	url := "/api/v1/people?page=0"
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 400
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestGetPeopleListHandlerSearch(t *testing.T) {
+	url := "/api/v1/people?search=sky"
+
+	mock := swapi.MockClient{
+		GetPeopleListFunc: func(ctx context.Context, page int, search string) (models.PeopleList, error) {
+			if search != "sky" {
+				t.Errorf("Assertion error. Expected: %s, Got: %s", "sky", search)
+			}
+
+			return models.PeopleList{Count: 0, Results: []models.People{}}, nil
+		},
+		GetPeopleListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestGetStarshipsHandlerSearchTooLong(t *testing.T) {
+	url := "/api/v1/starships?search=" + strings.Repeat("x", 101)
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 400
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestGetStarshipsHandlerSearchInvalid(t *testing.T) {
+	url := "/api/v1/starships?search=death%00star"
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 400
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestGetStarshipHandlerGatewayTimeout(t *testing.T) {
+	url := "/api/v1/starships/9"
+	expectedError := 504
+
+	mock := swapi.MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{}, errors.NewTimeout()
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetPeopleHandlerCanceled(t *testing.T) {
+	url := "/api/v1/people/1"
+	expectedError := 499
+
+	mock := swapi.MockClient{
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			return models.People{}, errors.NewCanceled()
+		},
+		GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+}
+
+func TestGetStarshipHandlerServiceUnavailable(t *testing.T) {
+	url := "/api/v1/starships/9"
+	expectedError := 503
+
+	mock := swapi.MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{}, errors.NewUnavailable()
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	expectedBody := `{"type":"SERVICE_UNAVAILABLE","message":"Upstream unavailable."}`
+
+	if response.StatusCode != expectedError {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", expectedError, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetStarshipHandlerNoCache(t *testing.T) {
+	url := "/api/v1/starships/9"
+
+	mock := swapi.MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{Name: "Death Star"}, nil
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 3},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	swapi.Instance = swapi.NewCache(&mock, swapi.DefaultCacheConfig)
+
+	DoRequest(http.MethodGet, url, http.Header{}, "")
+	DoRequest(http.MethodGet, url, http.Header{}, "")
+	DoRequest(http.MethodGet, url, http.Header{"Cache-Control": []string{"no-cache"}}, "")
+	DoRequest(http.MethodGet, url, http.Header{"Cache-Control": []string{"max-age=0, No-Cache"}}, "")
+}
+
+func TestGetStarshipHandlerExpand(t *testing.T) {
+	url := "/api/v1/starships/12?expand=pilots,films"
+
+	mock := swapi.MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{
+				Name: "X-wing",
+				Films: []string{
+					"https://swapi.dev/api/films/1/",
+				},
+				Pilots: []string{
+					"https://swapi.dev/api/people/1/",
+					"https://swapi.dev/api/people/99/",
+				},
+			}, nil
+		},
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			if id == 99 {
+				return models.People{}, errors.NewNotFound("people", "99")
+			}
+
+			return models.People{Name: "Luke Skywalker"}, nil
+		},
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			return models.Film{Title: "A New Hope", EpisodeID: 4}, nil
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		GetPeopleFuncControl:   mockeable.CallsFuncControl{ExpectedCalls: 2},
+		GetFilmFuncControl:     mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"id":0,"name":"X-wing","model":"","starship_class":"","manufacturer":"","cost_in_credits":"","length":"","crew":"","passengers":"","max_atmosphering_speed":"","hyperdrive_rating":"","MGLT":"","cargo_capacity":"","consumables":"","films":[{"title":"A New Hope","episode_id":4,"opening_crawl":"","director":"","producer":"","release_date":"","characters":null,"planets":null,"starships":null,"vehicles":null,"species":null}],"pilots":[{"id":0,"name":"Luke Skywalker","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":"","films":null,"species":null,"starships":null,"vehicles":null,"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""}],"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":"","unresolved":["http://example.com/api/v1/people/99"]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetStarshipHandlerExpandOnlySelectedFields(t *testing.T) {
+	url := "/api/v1/starships/12?expand=pilots&fields=name,films"
+
+	mock := swapi.MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{
+				Name:   "X-wing",
+				Films:  []string{"https://swapi.dev/api/films/1/"},
+				Pilots: []string{"https://swapi.dev/api/people/99/"},
+			}, nil
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"name":"X-wing","films":["http://example.com/api/v1/films/1"]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetPeopleListHandlerExpand(t *testing.T) {
+	url := "/api/v1/people?expand=homeworld"
+
+	mock := swapi.MockClient{
+		GetPeopleListFunc: func(ctx context.Context, page int, search string) (models.PeopleList, error) {
+			return models.PeopleList{
+				Count: 2,
+				Results: []models.People{
+					{Name: "Luke Skywalker", Homeworld: "https://swapi.dev/api/planets/1/"},
+					{Name: "C-3PO", Homeworld: "https://swapi.dev/api/planets/1/"},
+				},
+			}, nil
+		},
+		GetPlanetFunc: func(ctx context.Context, id int) (models.Planet, error) {
+			return models.Planet{Name: "Tatooine"}, nil
+		},
+		GetPeopleListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		GetPlanetFuncControl:     mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	planet := `{"name":"Tatooine","rotation_period":"","orbital_period":"","diameter":"","climate":"","gravity":"","terrain":"","surface_water":"","population":"","residents":null,"films":null}`
+	expectedBody := `{"count":2,"next":null,"previous":null,"results":[` +
+		`{"id":0,"name":"Luke Skywalker","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":` + planet + `,"films":null,"species":null,"starships":null,"vehicles":null,"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""},` +
+		`{"id":0,"name":"C-3PO","birth_year":"","eye_color":"","gender":"","hair_color":"","height":"","mass":"","skin_color":"","homeworld":` + planet + `,"films":null,"species":null,"starships":null,"vehicles":null,"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetStarshipHandlerExpandUnknownField(t *testing.T) {
+	url := "/api/v1/starships/12?expand=pilots,homeworld"
+
+	mock := swapi.MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{Name: "X-wing"}, nil
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 400
+	expectedBody := `{"type":"BAD_REQUEST","message":"Bad request. Reason: unknown expand fields: homeworld"}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetStarshipHandlerTypedFormat(t *testing.T) {
+	url := "/api/v1/starships/2?format=typed"
+
+	mock := swapi.MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{
+				Name:                 "CR90 corvette",
+				Model:                "CR90 corvette",
+				Class:                "corvette",
+				Manufacturer:         "Corellian Engineering Corporation",
+				CostInCredits:        "3500000",
+				Length:               "150",
+				Crew:                 "30-165",
+				Passengers:           "n/a",
+				MaxAtmospheringSpeed: "950",
+				HyperdriveRating:     "2.0",
+				MGLT:                 "60",
+				CargoCapacity:        "unknown",
+				Consumables:          "1 year",
+				Films:                []string{},
+				Pilots:               []string{},
+			}, nil
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"id":0,"name":"CR90 corvette","model":"CR90 corvette","starship_class":"corvette","manufacturer":"Corellian Engineering Corporation","cost_in_credits":3500000,"length":150,"crew":{"min":30,"max":165},"passengers":null,"max_atmosphering_speed":950,"hyperdrive_rating":2,"MGLT":60,"cargo_capacity":null,"consumables":"1 year","films":[],"pilots":[],"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetPeopleListHandlerTypedFormat(t *testing.T) {
+	url := "/api/v1/people?format=typed"
+
+	mock := swapi.MockClient{
+		GetPeopleListFunc: func(ctx context.Context, page int, search string) (models.PeopleList, error) {
+			return models.PeopleList{
+				Count: 1,
+				Results: []models.People{
+					{Name: "Jabba Desilijic Tiure", Height: "175", Mass: "1,358"},
+				},
+			}, nil
+		},
+		GetPeopleListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"count":1,"next":null,"previous":null,"results":[{"id":0,"name":"Jabba Desilijic Tiure","birth_year":"","eye_color":"","gender":"","hair_color":"","height":175,"mass":1358,"skin_color":"","homeworld":"","films":null,"species":null,"starships":null,"vehicles":null,"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":""}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetFilmHandlerTypedFormatUnavailable(t *testing.T) {
+	url := "/api/v1/films/1?format=typed"
+
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			return models.Film{}, nil
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 400
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestGetFilmHandlerRewritesMirrorLinks(t *testing.T) {
+	SetUpstreamBaseURL("http://mirror.local/swapi")
+	defer SetUpstreamBaseURL(swapi.DefaultBaseURL)
+
+	url := "/api/v1/films/1?fields=url,planets"
+
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			return models.Film{URL: "http://mirror.local/swapi/films/1/", Planets: []string{"https://swapi.dev/api/planets/1/"}}, nil
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	expectedBody := `{"planets":["https://swapi.dev/api/planets/1/"],"url":"http://example.com/api/v1/films/1"}`
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetFilmHandlerIgnoresForwardedHostByDefault(t *testing.T) {
+	url := "/api/v1/films/1?fields=url"
+
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			return models.Film{URL: "https://swapi.dev/api/films/1/"}, nil
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	headers := http.Header{}
+	headers.Set("X-Forwarded-Proto", "https")
+	headers.Set("X-Forwarded-Host", "evil.example")
+
+	response := DoRequest(http.MethodGet, url, headers, "")
+	expectedBody := `{"url":"http://example.com/api/v1/films/1"}`
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetStarshipsHandlerLastModified(t *testing.T) {
+	url := "/api/v1/starships"
+
+	mock := swapi.MockClient{
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
+			return models.Starships{
+				Count: 2,
+				Results: []models.Starship{
+					{Name: "CR90 corvette", Edited: time.Date(2014, 12, 20, 21, 23, 49, 867000000, time.UTC)},
+					{Name: "Star Destroyer", Edited: time.Date(2014, 12, 22, 17, 35, 44, 410000000, time.UTC)},
+				},
+			}, nil
+		},
+		GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	lastModifiedExpected := "Mon, 22 Dec 2014 17:35:44 GMT"
+
+	if response.Headers.Get("Last-Modified") != lastModifiedExpected {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", lastModifiedExpected, response.Headers.Get("Last-Modified"))
+	}
+}
+
+func TestGetFilmHandlerWithoutLastModified(t *testing.T) {
+	url := "/api/v1/films/1"
+
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			return models.Film{}, nil
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+
+	if response.Headers.Get("Last-Modified") != "" {
+		t.Errorf("Assertion error. Expected no Last-Modified, Got: %s", response.Headers.Get("Last-Modified"))
+	}
+}
+
+func TestGetStarshipHandlerFields(t *testing.T) {
+	url := "/api/v1/starships/2?fields=name,model,crew"
+
+	mock := swapi.MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{Name: "CR90 corvette", Model: "CR90 corvette", Crew: "30-165", Films: []string{"https://swapi.dev/api/films/1/"}}, nil
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"name":"CR90 corvette","model":"CR90 corvette","crew":"30-165"}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetPeopleListHandlerFields(t *testing.T) {
+	url := "/api/v1/people?fields=id,name"
+
+	mock := swapi.MockClient{
+		GetPeopleListFunc: func(ctx context.Context, page int, search string) (models.PeopleList, error) {
+			return models.PeopleList{
+				Count: 2,
+				Results: []models.People{
+					{ID: 1, Name: "Luke Skywalker", Height: "172"},
+					{ID: 2, Name: "C-3PO", Height: "167"},
+				},
+			}, nil
+		},
+		GetPeopleListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"count":2,"next":null,"previous":null,"results":[{"id":1,"name":"Luke Skywalker"},{"id":2,"name":"C-3PO"}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetFilmHandlerUnknownFields(t *testing.T) {
+	url := "/api/v1/films/1?fields=title,name,crew"
+
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			return models.Film{Title: "A New Hope"}, nil
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 400
+	expectedBody := `{"type":"BAD_REQUEST","message":"Bad request. Reason: unknown fields: name, crew"}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetStarshipsHandlerSortAndFilter(t *testing.T) {
+	url := "/api/v1/starships?sort=-length,name&crew[gte]=100&fields=name,length,crew"
+
+	mock := swapi.MockClient{
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
+			return models.Starships{
+				Count: 5,
+				Results: []models.Starship{
+					{Name: "X-wing", Length: "12.5", Crew: "1"},
+					{Name: "CR90 corvette", Length: "150", Crew: "30-165"},
+					{Name: "Star Destroyer", Length: "1,600", Crew: "47,060"},
+					{Name: "Executor", Length: "19000", Crew: "279,144"},
+					{Name: "Millennium Falcon", Length: "34.37", Crew: "unknown"},
+				},
+			}, nil
+		},
+		GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"count":2,"next":null,"previous":null,"results":[{"name":"Executor","length":"19000","crew":"279,144"},{"name":"Star Destroyer","length":"1,600","crew":"47,060"}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetStarshipsHandlerSortDescendingKeepsPlaceholdersLast(t *testing.T) {
+	url := "/api/v1/starships?sort=-length,name&fields=name,length"
+
+	mock := swapi.MockClient{
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
+			return models.Starships{
+				Count: 4,
+				Results: []models.Starship{
+					{Name: "Slave 1", Length: "unknown"},
+					{Name: "Star Destroyer", Length: "1,600"},
+					{Name: "Death Star", Length: "n/a"},
+					{Name: "CR90 corvette", Length: "150"},
+				},
+			}, nil
+		},
+		GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"count":4,"next":null,"previous":null,"results":[{"name":"Star Destroyer","length":"1,600"},{"name":"CR90 corvette","length":"150"},{"name":"Death Star","length":"n/a"},{"name":"Slave 1","length":"unknown"}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetStarshipsHandlerSortValuesWithUnitsAsText(t *testing.T) {
+	url := "/api/v1/starships?sort=consumables&fields=name,consumables"
+
+	mock := swapi.MockClient{
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
+			return models.Starships{
+				Count: 4,
+				Results: []models.Starship{
+					{Name: "Star Destroyer", Consumables: "2 years"},
+					{Name: "X-wing", Consumables: "1 week"},
+					{Name: "Millennium Falcon", Consumables: "6 months"},
+					{Name: "Slave 1", Consumables: "10 days"},
+				},
+			}, nil
+		},
+		GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"count":4,"next":null,"previous":null,"results":[{"name":"X-wing","consumables":"1 week"},{"name":"Slave 1","consumables":"10 days"},{"name":"Star Destroyer","consumables":"2 years"},{"name":"Millennium Falcon","consumables":"6 months"}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetPeopleListHandlerFilterByValue(t *testing.T) {
+	url := "/api/v1/people?gender=female&sort=name&fields=name&_=123&utm_source=mail&Gender=male"
+
+	mock := swapi.MockClient{
+		GetPeopleListFunc: func(ctx context.Context, page int, search string) (models.PeopleList, error) {
+			return models.PeopleList{
+				Count: 3,
+				Results: []models.People{
+					{Name: "Luke Skywalker", Gender: "male"},
+					{Name: "Leia Organa", Gender: "female"},
+					{Name: "Beru Whitesun lars", Gender: "Female"},
+				},
+			}, nil
+		},
+		GetPeopleListFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, url, nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"count":2,"next":null,"previous":null,"results":[{"name":"Beru Whitesun lars"},{"name":"Leia Organa"}]}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestGetStarshipsHandlerInvalidQuery(t *testing.T) {
+	cases := map[string]string{
+		"/api/v1/starships?sort=pilots,speed":       `{"type":"BAD_REQUEST","message":"Bad request. Reason: unknown sort fields: pilots, speed"}`,
+		"/api/v1/starships?gender[eq]=female":       `{"type":"BAD_REQUEST","message":"Bad request. Reason: unknown filter fields: gender[eq]"}`,
+		"/api/v1/starships?crew[gte]=many":          `{"type":"BAD_REQUEST","message":"Bad request. Reason: crew[gte] needs a numeric value, got: many"}`,
+		"/api/v1/starships?crew[in]=1":              `{"type":"BAD_REQUEST","message":"Bad request. Reason: invalid filter operator: crew[in]"}`,
+		"/api/v1/starships?sort=name&page=2":        `{"type":"BAD_REQUEST","message":"Bad request. Reason: sort and filters apply to the full collection and cannot be combined with page"}`,
+		"/api/v1/starships?starship_class=x&page=1": `{"type":"BAD_REQUEST","message":"Bad request. Reason: sort and filters apply to the full collection and cannot be combined with page"}`,
+	}
+
+	for url, expectedBody := range cases {
+		mock := swapi.MockClient{
+			GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
+				return models.Starships{Results: []models.Starship{}}, nil
+			},
+			GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+		}
+
+		mock.Use()
+
+		response := DoRequest(http.MethodGet, url, nil, "")
+		statusCodeExpected := 400
+
+		if response.StatusCode != statusCodeExpected {
+			t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+		}
+
+		if response.StringBody() != expectedBody {
+			t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+		}
+
+		mockeable.CleanUpAndAssertControls(t, &mock)
+	}
+}
+
+func TestHealthHandler(t *testing.T) {
+	response := DoRequest(http.MethodGet, "/health", nil, "")
+	statusCodeExpected := 200
+	expectedBody := `{"status":"ok"}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+}
+
+func TestReadyHandlerCachesProbe(t *testing.T) {
+	probe = &upstreamProbe{now: time.Now}
+	defer func() { probe = &upstreamProbe{now: time.Now} }()
+
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			return models.Film{}, errors.NewNotFound("films", "1")
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	statusCodeExpected := 200
+	expectedBody := `{"status":"ready","checks":{"swapi":{"status":"up"}}}`
+
+	for i := 0; i < 2; i++ {
+		response := DoRequest(http.MethodGet, "/ready", nil, "")
+
+		if response.StatusCode != statusCodeExpected {
+			t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+		}
+
+		if response.StringBody() != expectedBody {
+			t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+		}
+	}
+}
+
+func TestReadyHandlerUpstreamDown(t *testing.T) {
+	now := time.Now()
+	probe = &upstreamProbe{now: func() time.Time { return now }}
+	defer func() { probe = &upstreamProbe{now: time.Now} }()
+
+	calls := 0
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			calls++
+
+			if calls == 1 {
+				return models.Film{}, errors.NewUnavailable()
+			}
+
+			return models.Film{}, nil
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	response := DoRequest(http.MethodGet, "/ready", nil, "")
+	statusCodeExpected := 503
+	expectedBody := `{"status":"not_ready","checks":{"swapi":{"status":"down","error":"Upstream unavailable."}}}`
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+
+	if response.StringBody() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.StringBody())
+	}
+
+	now = now.Add(probeTTL)
+	response = DoRequest(http.MethodGet, "/ready", nil, "")
+	statusCodeExpected = 200
+
+	if response.StatusCode != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
+	}
+}
+
+func TestReadyHandlerShuttingDown(t *testing.T) {
+	setShuttingDown(true)
+	defer setShuttingDown(false)
+
+	response := DoRequest(http.MethodGet, "/ready", nil, "")
+	statusCodeExpected := 503
+	expectedBody := `{"status":"shutting_down"}`
 
 	if response.StatusCode != statusCodeExpected {
 		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.StatusCode)
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/clients/swapi"
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/httphelpers"
+)
+
+const (
+	// probeTTL is how long the result of an upstream probe is reused.
+	probeTTL = 5 * time.Second
+
+	// probeTimeout bounds a single upstream probe.
+	probeTimeout = 2 * time.Second
+)
+
+// healthCheck is the status of a single dependency.
+type healthCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type healthResponse struct {
+	Status string                 `json:"status"`
+	Checks map[string]healthCheck `json:"checks,omitempty"`
+}
+
+// shuttingDown is set once the server starts draining, so that /ready
+// reports it before connections are refused.
+var shuttingDown int32
+
+func setShuttingDown(value bool) {
+	var flag int32
+
+	if value {
+		flag = 1
+	}
+
+	atomic.StoreInt32(&shuttingDown, flag)
+}
+
+// upstreamProbe caches the last check of the SWAPI client.
+type upstreamProbe struct {
+	mu        sync.Mutex
+	now       func() time.Time
+	checkedAt time.Time
+	result    healthCheck
+}
+
+var probe = &upstreamProbe{now: time.Now}
+
+// check fetches a single film, skipping the cache, unless a recent result
+// can be reused. A not found answer still proves the upstream is reachable.
+func (p *upstreamProbe) check() healthCheck {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.checkedAt.IsZero() && p.now().Sub(p.checkedAt) < probeTTL {
+		return p.result
+	}
+
+	ctx, cancel := context.WithTimeout(swapi.WithoutCache(context.Background()), probeTimeout)
+	defer cancel()
+
+	_, err := swapi.Instance.GetFilm(ctx, 1)
+
+	p.result = healthCheck{Status: "up"}
+
+	if err != nil && errors.Status(err) != http.StatusNotFound {
+		p.result = healthCheck{Status: "down", Error: err.Error()}
+	}
+
+	p.checkedAt = p.now()
+
+	return p.result
+}
+
+// HealthHandler reports that the process is alive.
+func HealthHandler(rw http.ResponseWriter, r *http.Request) {
+	httphelpers.OK(rw, healthResponse{Status: "ok"})
+}
+
+// ReadyHandler reports whether the server can serve traffic: it is not
+// shutting down and the upstream API answers. It responds with a 503
+// otherwise.
+func ReadyHandler(rw http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&shuttingDown) == 1 {
+		httphelpers.JSON(rw, http.StatusServiceUnavailable, healthResponse{Status: "shutting_down"})
+		return
+	}
+
+	response := healthResponse{
+		Status: "ready",
+		Checks: map[string]healthCheck{"swapi": probe.check()},
+	}
+
+	for _, check := range response.Checks {
+		if check.Status != "up" {
+			response.Status = "not_ready"
+		}
+	}
+
+	if response.Status != "ready" {
+		httphelpers.JSON(rw, http.StatusServiceUnavailable, response)
+		return
+	}
+
+	httphelpers.OK(rw, response)
+}
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/klasrak/go-meli-test-dojo/clients/swapi"
+)
+
+// upstreamLink matches the links of the SWAPI server that is queried. It
+// follows swapi.DefaultBaseURL until SetUpstreamBaseURL is called.
+var upstreamLink = upstreamLinkPattern(swapi.DefaultBaseURL)
+
+// SetUpstreamBaseURL makes responses rewrite the links of the SWAPI server
+// at base, such as a mirror. It must be called before serving requests.
+func SetUpstreamBaseURL(base string) {
+	upstreamLink = upstreamLinkPattern(base)
+}
+
+// upstreamLinkPattern matches, inside encoded JSON, the links under the path
+// of base to a resource ("https://swapi.dev/api/people/1/") or to a page of
+// resources ("https://swapi.dev/api/people/?page=2"), whatever the upstream
+// host is.
+func upstreamLinkPattern(base string) *regexp.Regexp {
+	path := ""
+
+	if parsed, err := url.Parse(base); err == nil {
+		path = strings.TrimSuffix(parsed.Path, "/")
+	}
+
+	return regexp.MustCompile(`"https?://[^/"\\]+` + regexp.QuoteMeta(path) + `/(starships|people|films|planets|species|vehicles)/(?:(\d+)/?)?(\?[^"]*)?"`)
+}
+
+// validHost guards the host before it is written into responses.
+var validHost = regexp.MustCompile(`^[A-Za-z0-9.\-:\[\]]+$`)
+
+// rewriteLinks points every upstream link of an encoded JSON body to the
+// matching /api/v1 route under base.
+func rewriteLinks(body []byte, base string) []byte {
+	return upstreamLink.ReplaceAllFunc(body, func(match []byte) []byte {
+		parts := upstreamLink.FindSubmatch(match)
+		link := base + "/api/v1/" + string(parts[1])
+
+		if len(parts[2]) > 0 {
+			link += "/" + string(parts[2])
+		}
+
+		encoded, _ := json.Marshal(link)
+
+		// The query is copied as is since it is already JSON encoded.
+		return append(encoded[:len(encoded)-1], append(parts[3], '"')...)
+	})
+}
+
+// publicBaseURL is the scheme and host the caller used to reach us. Behind
+// a trusted proxy, ProxyHeaders sets them from the forwarded headers.
+func publicBaseURL(r *http.Request) string {
+	scheme := "http"
+
+	if r.TLS != nil {
+		scheme = "https"
+	}
+
+	if r.URL.Scheme == "http" || r.URL.Scheme == "https" {
+		scheme = r.URL.Scheme
+	}
+
+	host := r.Host
+
+	if !validHost.MatchString(host) {
+		host = "localhost"
+	}
+
+	return scheme + "://" + host
+}
+
+func firstHeaderValue(r *http.Request, name string) string {
+	value := r.Header.Get(name)
+
+	if index := strings.Index(value, ","); index >= 0 {
+		value = value[:index]
+	}
+
+	return strings.ToLower(strings.TrimSpace(value))
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/klasrak/go-meli-test-dojo/clients/swapi"
+	"github.com/klasrak/go-meli-test-dojo/metrics"
+)
+
+var (
+	registry = metrics.NewRegistry()
+
+	requestsTotal   = registry.Counter("http_requests_total", "Requests served, by route pattern and status.", "route", "status")
+	requestDuration = registry.Histogram("http_request_duration_seconds", "Time spent serving requests, by route pattern and status.", metrics.DefaultBuckets, "route", "status")
+
+	upstreamCallsTotal   = registry.Counter("swapi_calls_total", "Calls to the SWAPI client, by method and outcome.", "method", "outcome")
+	upstreamCallDuration = registry.Histogram("swapi_call_duration_seconds", "Time spent in calls to the SWAPI client, by method and outcome.", metrics.DefaultBuckets, "method", "outcome")
+
+	upstreamAttemptsTotal   = registry.Counter("swapi_attempts_total", "HTTP requests sent to SWAPI, retries included, by status.", "status")
+	upstreamAttemptDuration = registry.Histogram("swapi_attempt_duration_seconds", "Time spent in HTTP requests sent to SWAPI, by status.", metrics.DefaultBuckets, "status")
+)
+
+// Metrics counts the requests and measures their latency, labeled by route
+// pattern and status. Requests that match no route are labeled "unmatched".
+func Metrics(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(rw, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := routePattern(r)
+
+		if route == "" {
+			route = "unmatched"
+		}
+
+		status := ww.Status()
+
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		requestsTotal.Inc(route, strconv.Itoa(status))
+		requestDuration.Observe(time.Since(start).Seconds(), route, strconv.Itoa(status))
+	})
+}
+
+// MetricsHandler serves the metrics in the Prometheus text format.
+func MetricsHandler(rw http.ResponseWriter, r *http.Request) {
+	registry.Handler().ServeHTTP(rw, r)
+}
+
+// ObserveUpstream records a call to the SWAPI client in the upstream
+// metrics.
+func ObserveUpstream(call swapi.Call) {
+	upstreamCallsTotal.Inc(call.Method, call.Outcome)
+	upstreamCallDuration.Observe(call.Duration.Seconds(), call.Method, call.Outcome)
+}
+
+// ObserveAttempt records an HTTP request sent to SWAPI in the attempt
+// metrics. Attempts that got no response are labeled "error".
+func ObserveAttempt(attempt swapi.Attempt) {
+	status := "error"
+
+	if attempt.Err == nil {
+		status = strconv.Itoa(attempt.Status)
+	}
+
+	upstreamAttemptsTotal.Inc(status)
+	upstreamAttemptDuration.Observe(attempt.Duration.Seconds(), status)
+}
+
+// ObserveCache exposes the hit and miss counters of the SWAPI response
+// cache read from stats. It must be called once, before serving requests.
+func ObserveCache(stats func() swapi.CacheStats) {
+	registry.CounterFunc("swapi_cache_hits_total", "Lookups answered by the SWAPI response cache.", func() float64 {
+		return float64(stats().Hits)
+	})
+	registry.CounterFunc("swapi_cache_misses_total", "Lookups the SWAPI response cache could not answer.", func() float64 {
+		return float64(stats().Misses)
+	})
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/klasrak/go-meli-test-dojo/clients/swapi"
+)
+
+// NoCache lets callers skip the SWAPI response cache by sending a
+// "Cache-Control: no-cache" request header.
+func NoCache(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		for _, directive := range strings.Split(r.Header.Get("Cache-Control"), ",") {
+			if strings.EqualFold(strings.TrimSpace(directive), "no-cache") {
+				r = r.WithContext(swapi.WithoutCache(r.Context()))
+				break
+			}
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// ProxyHeaders takes the scheme and host the caller used from the
+// X-Forwarded-Proto and X-Forwarded-Host headers, and its address from the
+// last X-Forwarded-For entry, the one added by the proxy. It must only be
+// used behind a proxy that sets them, since callers can send any value.
+func ProxyHeaders(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		forwardedFor := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+
+		if ip := strings.TrimSpace(forwardedFor[len(forwardedFor)-1]); net.ParseIP(ip) != nil {
+			r.RemoteAddr = ip
+		}
+
+		if proto := firstHeaderValue(r, "X-Forwarded-Proto"); proto == "http" || proto == "https" {
+			r.URL.Scheme = proto
+		}
+
+		if host := firstHeaderValue(r, "X-Forwarded-Host"); validHost.MatchString(host) {
+			r.Host = host
+		}
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// validRequestID restricts the request ids accepted from callers, since they
+// end up in logs and upstream headers.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:\-]{1,128}$`)
+
+// RequestID keeps the X-Request-ID sent by the caller, or assigns a new one,
+// echoes it in the response and stores it in the request context so that
+// logs and upstream calls carry it.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(swapi.RequestIDHeader)
+
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		rw.Header().Set(swapi.RequestIDHeader, id)
+		next.ServeHTTP(rw, r.WithContext(swapi.WithRequestID(r.Context(), id)))
+	})
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+
+	if _, err := rand.Read(id); err != nil {
+		return time.Now().UTC().Format("20060102T150405.000000000Z")
+	}
+
+	return hex.EncodeToString(id)
+}
+
+// requestLog is the structured record written for every request.
+type requestLog struct {
+	Time      string  `json:"time"`
+	Level     string  `json:"level"`
+	Msg       string  `json:"msg"`
+	RequestID string  `json:"request_id"`
+	Method    string  `json:"method"`
+	Route     string  `json:"route"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	Bytes     int     `json:"bytes"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+// Logger writes one JSON line per request to out with its method, route
+// pattern, status, response size and latency. Server errors are logged with
+// the ERROR level and client errors with WARN.
+func Logger(out io.Writer) func(http.Handler) http.Handler {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(out)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ww := middleware.NewWrapResponseWriter(rw, r.ProtoMajor)
+
+			next.ServeHTTP(ww, r)
+
+			record := requestLog{
+				Time:      start.UTC().Format(time.RFC3339Nano),
+				Level:     "INFO",
+				Msg:       "request",
+				RequestID: swapi.RequestID(r.Context()),
+				Method:    r.Method,
+				Route:     routePattern(r),
+				Path:      r.URL.Path,
+				Status:    ww.Status(),
+				Bytes:     ww.BytesWritten(),
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+
+			if record.Status == 0 {
+				record.Status = http.StatusOK
+			}
+
+			switch {
+			case record.Status >= 500:
+				record.Level = "ERROR"
+			case record.Status >= 400:
+				record.Level = "WARN"
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			encoder.Encode(record)
+		})
+	}
+}
+
+// routePattern is the chi pattern that matched r, such as
+// "/api/v1/starships/{id}", or "" when no route matched.
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+
+	return ""
+}
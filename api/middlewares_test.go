@@ -0,0 +1,313 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/klasrak/go-meli-test-dojo/clients/swapi"
+	"github.com/klasrak/go-meli-test-dojo/config"
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/mockeable"
+	"github.com/klasrak/go-meli-test-dojo/models"
+)
+
+func newLoggedRouter(out *bytes.Buffer, requestIDs chan<- string) *chi.Mux {
+	router := chi.NewRouter()
+	router.Use(RequestID, Logger(out))
+	router.Get("/api/v1/starships/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		requestIDs <- swapi.RequestID(r.Context())
+		rw.WriteHeader(http.StatusNotFound)
+		rw.Write([]byte("not found"))
+	})
+
+	return router
+}
+
+func TestRequestIDAssigned(t *testing.T) {
+	requestIDs := make(chan string, 1)
+	router := newLoggedRouter(&bytes.Buffer{}, requestIDs)
+	response := httptest.NewRecorder()
+
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/api/v1/starships/9", nil))
+
+	id := <-requestIDs
+
+	if len(id) != 32 {
+		t.Errorf("Assertion error. Expected a 32 characters id, Got: %s", id)
+	}
+
+	if response.Header().Get("X-Request-ID") != id {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", id, response.Header().Get("X-Request-ID"))
+	}
+}
+
+func TestRequestIDAccepted(t *testing.T) {
+	requestIDs := make(chan string, 2)
+	router := newLoggedRouter(&bytes.Buffer{}, requestIDs)
+
+	for id, expected := range map[string]bool{"abc-123": true, "bad id\n": false} {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/starships/9", nil)
+		request.Header.Set("X-Request-ID", id)
+
+		router.ServeHTTP(httptest.NewRecorder(), request)
+
+		if got := <-requestIDs; (got == id) != expected {
+			t.Errorf("Assertion error. Expected %q to be accepted: %v, Got: %s", id, expected, got)
+		}
+	}
+}
+
+func TestLoggerWritesJSONRecord(t *testing.T) {
+	out := &bytes.Buffer{}
+	router := newLoggedRouter(out, make(chan string, 1))
+	request := httptest.NewRequest(http.MethodGet, "/api/v1/starships/9", nil)
+	request.Header.Set("X-Request-ID", "abc-123")
+
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	var record requestLog
+
+	if err := json.Unmarshal(out.Bytes(), &record); err != nil {
+		t.Fatalf("Assertion error. Expected a JSON record, Got: %s", out.String())
+	}
+
+	expected := requestLog{
+		Time:      record.Time,
+		Level:     "WARN",
+		Msg:       "request",
+		RequestID: "abc-123",
+		Method:    http.MethodGet,
+		Route:     "/api/v1/starships/{id}",
+		Path:      "/api/v1/starships/9",
+		Status:    http.StatusNotFound,
+		Bytes:     9,
+		LatencyMS: record.LatencyMS,
+	}
+
+	if record != expected {
+		t.Errorf("Assertion error. Expected: %+v, Got: %+v", expected, record)
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	mock := swapi.MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			return models.Film{}, errors.NewNotFound("films", "99")
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	DoRequest(http.MethodGet, "/api/v1/films/99", nil, "")
+	ObserveUpstream(swapi.Call{Method: "GetFilm", Outcome: swapi.OutcomeNotFound, Duration: time.Millisecond})
+	ObserveAttempt(swapi.Attempt{Resource: "films/99", Number: 1, Status: http.StatusServiceUnavailable, Duration: time.Millisecond})
+	ObserveAttempt(swapi.Attempt{Resource: "films/99", Number: 2, Err: context.DeadlineExceeded, Duration: time.Millisecond})
+	ObserveCache(func() swapi.CacheStats { return swapi.CacheStats{Hits: 2, Misses: 1} })
+
+	response := DoRequest(http.MethodGet, "/metrics", nil, "")
+
+	for _, expected := range []string{
+		`http_requests_total{route="/api/v1/films/{id}",status="404"} `,
+		`http_request_duration_seconds_bucket{route="/api/v1/films/{id}",status="404",le="+Inf"} `,
+		`swapi_calls_total{method="GetFilm",outcome="not_found"} `,
+		`swapi_call_duration_seconds_count{method="GetFilm",outcome="not_found"} `,
+		`swapi_attempts_total{status="503"} `,
+		`swapi_attempts_total{status="error"} `,
+		`swapi_attempt_duration_seconds_count{status="503"} `,
+		"swapi_cache_hits_total 2\n",
+		"swapi_cache_misses_total 1\n",
+	} {
+		if !strings.Contains(response.StringBody(), expected) {
+			t.Errorf("Assertion error. Expected %s in: %s", expected, response.StringBody())
+		}
+	}
+}
+
+func TestProxyHeadersRewriteLinks(t *testing.T) {
+	url := "/api/v1/starships?page=1&search=x"
+
+	mock := swapi.MockClient{
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
+			next := "https://swapi.dev/api/starships/?page=2&search=x"
+
+			return models.Starships{
+				Count: 11,
+				Next:  &next,
+				Results: []models.Starship{
+					{ID: 9, Name: "Death Star", Films: []string{"https://swapi.dev/api/films/1/"}, URL: "https://swapi.dev/api/starships/9/"},
+				},
+			}, nil
+		},
+		GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	request := httptest.NewRequest(http.MethodGet, url, nil)
+	request.Header.Set("X-Forwarded-Proto", "https")
+	request.Header.Set("X-Forwarded-Host", "api.example.org, proxy.internal")
+
+	router := chi.NewRouter()
+	URLMapping(router, io.Discard, ProxyHeaders)
+
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	statusCodeExpected := 200
+	expectedBody := `{"count":11,"next":"https://api.example.org/api/v1/starships?page=2\u0026search=x","previous":null,"results":[{"id":9,"name":"Death Star","model":"","starship_class":"","manufacturer":"","cost_in_credits":"","length":"","crew":"","passengers":"","max_atmosphering_speed":"","hyperdrive_rating":"","MGLT":"","cargo_capacity":"","consumables":"","films":["https://api.example.org/api/v1/films/1"],"pilots":null,"created":"0001-01-01T00:00:00Z","edited":"0001-01-01T00:00:00Z","url":"https://api.example.org/api/v1/starships/9"}]}`
+
+	if response.Code != statusCodeExpected {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", statusCodeExpected, response.Code)
+	}
+
+	if response.Body.String() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.Body.String())
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Now()
+	limiter := NewRateLimiter(config.RateLimit{Burst: 2, Refill: 0.5, APIKeys: []string{"client-key"}, MaxClients: 10})
+	limiter.now = func() time.Time { return now }
+
+	router := chi.NewRouter()
+	router.Use(RateLimit(limiter))
+	router.Get("/api/v1/films/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	serve := func(apiKey string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/films/1", nil)
+
+		if apiKey != "" {
+			request.Header.Set(APIKeyHeader, apiKey)
+		}
+
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+
+		return response
+	}
+
+	for i, expected := range []string{"1", "0"} {
+		response := serve("")
+
+		if response.Code != http.StatusOK {
+			t.Errorf("Assertion error. Expected: %d, Got: %d", http.StatusOK, response.Code)
+		}
+
+		if response.Header().Get("RateLimit-Remaining") != expected {
+			t.Errorf("Assertion error. Request %d expected remaining: %s, Got: %s", i, expected, response.Header().Get("RateLimit-Remaining"))
+		}
+	}
+
+	response := serve("")
+	expectedBody := `{"type":"TOO_MANY_REQUESTS","message":"Too many requests."}`
+	expectedHeaders := map[string]string{
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "4",
+		"Retry-After":         "2",
+	}
+
+	if response.Code != http.StatusTooManyRequests {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", http.StatusTooManyRequests, response.Code)
+	}
+
+	if response.Body.String() != expectedBody {
+		t.Errorf("Assertion error. Expected: %s, Got: %s", expectedBody, response.Body.String())
+	}
+
+	for name, expected := range expectedHeaders {
+		if response.Header().Get(name) != expected {
+			t.Errorf("Assertion error. Expected %s: %s, Got: %s", name, expected, response.Header().Get(name))
+		}
+	}
+
+	if response := serve("unknown-key"); response.Code != http.StatusTooManyRequests {
+		t.Errorf("Assertion error. Expected unknown API keys to share the IP bucket, Got: %d", response.Code)
+	}
+
+	if response := serve("client-key"); response.Code != http.StatusOK {
+		t.Errorf("Assertion error. Expected configured API keys to have their own bucket, Got: %d", response.Code)
+	}
+
+	now = now.Add(2 * time.Second)
+
+	if response := serve(""); response.Code != http.StatusOK {
+		t.Errorf("Assertion error. Expected a refilled token, Got: %d", response.Code)
+	}
+}
+
+func TestRateLimitCapsClients(t *testing.T) {
+	now := time.Now()
+	limiter := NewRateLimiter(config.RateLimit{Burst: 2, Refill: 0.5, MaxClients: 2})
+	limiter.now = func() time.Time { return now }
+
+	for _, key := range []string{"ip:10.0.0.1", "ip:10.0.0.2", "ip:10.0.0.3"} {
+		limiter.take(key)
+		now = now.Add(time.Millisecond)
+	}
+
+	if len(limiter.buckets) != 2 {
+		t.Errorf("Assertion error. Expected: %d, Got: %d", 2, len(limiter.buckets))
+	}
+
+	if _, ok := limiter.buckets["ip:10.0.0.1"]; ok {
+		t.Errorf("Assertion error. Expected the least recently used bucket to be evicted")
+	}
+}
+
+func TestRateLimitByForwardedAddress(t *testing.T) {
+	router := chi.NewRouter()
+	router.Use(ProxyHeaders, RateLimit(NewRateLimiter(config.RateLimit{Burst: 1, Refill: 1, MaxClients: 10})))
+	router.Get("/api/v1/films/{id}", func(rw http.ResponseWriter, r *http.Request) {
+		rw.WriteHeader(http.StatusOK)
+	})
+
+	cases := []struct {
+		forwardedFor string
+		expected     int
+	}{
+		{"203.0.113.1", http.StatusOK},
+		{"198.51.100.7, 203.0.113.2", http.StatusOK},
+		{"203.0.113.2", http.StatusTooManyRequests},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(http.MethodGet, "/api/v1/films/1", nil)
+		request.Header.Set("X-Forwarded-For", c.forwardedFor)
+
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+
+		if response.Code != c.expected {
+			t.Errorf("Assertion error. For %s expected: %d, Got: %d", c.forwardedFor, c.expected, response.Code)
+		}
+	}
+}
+
+func TestRateLimitSkipsHealthChecks(t *testing.T) {
+	router := chi.NewRouter()
+	URLMapping(router, io.Discard, RateLimit(NewRateLimiter(config.RateLimit{Burst: 1, Refill: 1})))
+
+	for i := 0; i < 3; i++ {
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		if response.Code != http.StatusOK {
+			t.Errorf("Assertion error. Expected: %d, Got: %d", http.StatusOK, response.Code)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/models"
+)
+
+// reservedParams are the query parameters that are not filters.
+var reservedParams = []string{"page", "search", "expand", "fields", "format", "sort"}
+
+// filterParam matches filter parameters such as "gender" or "crew[gte]".
+var filterParam = regexp.MustCompile(`^([A-Za-z_]+)(?:\[([a-z]+)\])?$`)
+
+// filterOperators lists the comparisons a filter can use. Every one but
+// "eq" needs a numeric value.
+var filterOperators = []string{"eq", "gt", "gte", "lt", "lte"}
+
+// sortKey orders list results by a field, descending when desc is set.
+type sortKey struct {
+	field string
+	desc  bool
+}
+
+// filter keeps the list results whose field compares to value with op.
+type filter struct {
+	field string
+	op    string
+	value string
+}
+
+// parseQuery reads the sort and filter parameters of a list of resource.
+// A parameter is a filter when it names a scalar field of the resource or
+// uses the field[op] syntax; other parameters, such as cache busters, are
+// ignored. Sort and filters apply to the full collection, so they cannot be
+// combined with page.
+func parseQuery(r *http.Request, resource string) ([]sortKey, []filter, error) {
+	allowed := queryable[resource]
+	keys := []sortKey{}
+	filters := []filter{}
+	unknown := []string{}
+
+	for _, field := range listParam(r, "sort") {
+		key := sortKey{field: strings.TrimPrefix(field, "-"), desc: strings.HasPrefix(field, "-")}
+
+		if len(unknownFields([]string{key.field}, allowed)) > 0 {
+			unknown = append(unknown, key.field)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if len(unknown) > 0 {
+		return nil, nil, errors.NewBadRequest(fmt.Sprintf("unknown sort fields: %s", strings.Join(unknown, ", ")))
+	}
+
+	names := []string{}
+
+	for name := range r.URL.Query() {
+		if len(unknownFields([]string{name}, reservedParams)) > 0 {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		match := filterParam.FindStringSubmatch(name)
+
+		if match == nil {
+			continue
+		}
+
+		if len(unknownFields([]string{match[1]}, allowed)) > 0 {
+			if match[2] != "" {
+				unknown = append(unknown, name)
+			}
+
+			continue
+		}
+
+		f := filter{field: match[1], op: match[2], value: r.URL.Query().Get(name)}
+
+		if f.op == "" {
+			f.op = "eq"
+		}
+
+		if len(unknownFields([]string{f.op}, filterOperators)) > 0 {
+			return nil, nil, errors.NewBadRequest(fmt.Sprintf("invalid filter operator: %s", name))
+		}
+
+		if _, ok := numericValue(f.value); f.op != "eq" && !ok {
+			return nil, nil, errors.NewBadRequest(fmt.Sprintf("%s needs a numeric value, got: %s", name, f.value))
+		}
+
+		filters = append(filters, f)
+	}
+
+	if len(unknown) > 0 {
+		return nil, nil, errors.NewBadRequest(fmt.Sprintf("unknown filter fields: %s", strings.Join(unknown, ", ")))
+	}
+
+	if (len(keys) > 0 || len(filters) > 0) && r.URL.Query().Get("page") != "" {
+		return nil, nil, errors.NewBadRequest("sort and filters apply to the full collection and cannot be combined with page")
+	}
+
+	return keys, filters, nil
+}
+
+// queryable lists, per resource, the scalar fields that can be used to sort
+// and filter lists.
+var queryable = map[string][]string{
+	"starships": scalarFields(models.Starship{}),
+	"people":    scalarFields(models.People{}),
+	"films":     scalarFields(models.Film{}),
+	"planets":   scalarFields(models.Planet{}),
+	"species":   scalarFields(models.Species{}),
+	"vehicles":  scalarFields(models.Vehicle{}),
+}
+
+// scalarFields returns the JSON names of the string, integer and time fields
+// of v.
+func scalarFields(v interface{}) []string {
+	fields := []string{}
+	t := reflect.TypeOf(v)
+
+	for i := 0; i < t.NumField(); i++ {
+		name := jsonName(t.Field(i))
+
+		if _, ok := scalar(reflect.Zero(t.Field(i).Type)); ok && name != "" {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// query returns a copy of the list data with the results matching every
+// filter, ordered by keys. The count is updated to the matching results.
+func query(data interface{}, keys []sortKey, filters []filter) interface{} {
+	list := reflect.ValueOf(data)
+	results := list.FieldByName("Results")
+	matching := reflect.MakeSlice(results.Type(), 0, results.Len())
+
+	for i := 0; i < results.Len(); i++ {
+		if matchesFilters(results.Index(i), filters) {
+			matching = reflect.Append(matching, results.Index(i))
+		}
+	}
+
+	sort.SliceStable(matching.Interface(), func(i, j int) bool {
+		for _, key := range keys {
+			a, _ := fieldValue(matching.Index(i), key.field)
+			b, _ := fieldValue(matching.Index(j), key.field)
+
+			// Numbers come before other values and placeholders last in
+			// both directions, only values of the same kind are reversed.
+			// Placeholders tie, leaving the order to the next key.
+			x, y := valueKind(a), valueKind(b)
+
+			if x != y {
+				return x < y
+			}
+
+			if x == placeholderKind {
+				continue
+			}
+
+			if c := compareValues(a, b); c != 0 {
+				return (c < 0) != key.desc
+			}
+		}
+
+		return false
+	})
+
+	result := reflect.New(list.Type()).Elem()
+	result.Set(list)
+	result.FieldByName("Results").Set(matching)
+	result.FieldByName("Count").SetInt(int64(matching.Len()))
+
+	return result.Interface()
+}
+
+func matchesFilters(item reflect.Value, filters []filter) bool {
+	for _, f := range filters {
+		value, _ := fieldValue(item, f.field)
+
+		if f.op == "eq" {
+			if a, ok := numericValue(value); ok {
+				if b, ok := numericValue(f.value); ok {
+					if a != b {
+						return false
+					}
+
+					continue
+				}
+			}
+
+			if !strings.EqualFold(value, f.value) {
+				return false
+			}
+
+			continue
+		}
+
+		a, ok := numericValue(value)
+
+		if !ok {
+			return false
+		}
+
+		b, _ := numericValue(f.value)
+
+		switch f.op {
+		case "gt":
+			ok = a > b
+		case "gte":
+			ok = a >= b
+		case "lt":
+			ok = a < b
+		case "lte":
+			ok = a <= b
+		}
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Kinds of values, in the order they are sorted.
+const (
+	numericKind = iota
+	textKind
+	placeholderKind
+)
+
+// valueKind tells numbers apart from other values and from the placeholders
+// SWAPI uses for missing values, such as "unknown" or "n/a".
+func valueKind(value string) int {
+	if _, ok := numericValue(value); ok {
+		return numericKind
+	}
+
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "unknown", "n/a":
+		return placeholderKind
+	}
+
+	return textKind
+}
+
+// compareValues orders two values of the same kind: numbers numerically and
+// anything else as case insensitive strings.
+func compareValues(a string, b string) int {
+	x, xNumeric := numericValue(a)
+	y, yNumeric := numericValue(b)
+
+	switch {
+	case xNumeric && yNumeric && x < y:
+		return -1
+	case xNumeric && yNumeric && x > y:
+		return 1
+	case xNumeric && yNumeric:
+		return 0
+	default:
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	}
+}
+
+// plainNumber and plainRange match SWAPI numbers such as "1,600" or "12.5"
+// and ranges such as "30-165", without units or other suffixes.
+var (
+	plainNumber = regexp.MustCompile(`^[0-9][0-9,]*(\.[0-9]+)?$`)
+	plainRange  = regexp.MustCompile(`^[0-9][0-9,]*-[0-9][0-9,]*$`)
+)
+
+// numericValue reads plain SWAPI numbers. Ranges take their lower bound.
+// Values with a suffix, like "2 years" or "19BBY", are not numeric since
+// their unit matters to the order.
+func numericValue(value string) (float64, bool) {
+	value = strings.TrimSpace(value)
+
+	if plainNumber.MatchString(value) {
+		return models.ParseFloat(value)
+	}
+
+	if plainRange.MatchString(value) {
+		if r, ok := models.ParseRange(value); ok {
+			return float64(r.Min), true
+		}
+	}
+
+	return 0, false
+}
+
+// fieldValue returns, as a string, the field of item encoded as name.
+func fieldValue(item reflect.Value, name string) (string, bool) {
+	for i := 0; i < item.NumField(); i++ {
+		if jsonName(item.Type().Field(i)) == name {
+			return scalar(item.Field(i))
+		}
+	}
+
+	return "", false
+}
+
+func scalar(v reflect.Value) (string, bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), true
+	case reflect.Int, reflect.Int64:
+		return fmt.Sprintf("%d", v.Int()), true
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return "", true
+		}
+
+		// A fixed width layout keeps times ordered as strings.
+		return t.UTC().Format("2006-01-02T15:04:05.000000000Z"), true
+	}
+
+	return "", false
+}
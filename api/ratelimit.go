@@ -0,0 +1,194 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/config"
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/httphelpers"
+)
+
+// APIKeyHeader identifies a client across addresses. Clients without it, or
+// with a key that is not configured, are limited by IP address.
+const APIKeyHeader = "X-API-Key"
+
+// sweepInterval is how often buckets that refilled completely, and so hold
+// no state worth keeping, are dropped.
+const sweepInterval = time.Minute
+
+// RateLimiter keeps one token bucket per client. Each request takes a token;
+// buckets hold up to burst tokens and regain refill tokens per second. At
+// most maxClients buckets are kept.
+type RateLimiter struct {
+	burst      float64
+	refill     float64
+	keys       map[string]bool
+	maxClients int
+	now        func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// rateDecision is the outcome of taking a token for a client.
+type rateDecision struct {
+	allowed    bool
+	remaining  int
+	reset      time.Duration
+	retryAfter time.Duration
+}
+
+func NewRateLimiter(cfg config.RateLimit) *RateLimiter {
+	keys := map[string]bool{}
+
+	for _, key := range cfg.APIKeys {
+		keys[key] = true
+	}
+
+	return &RateLimiter{
+		burst:      float64(cfg.Burst),
+		refill:     cfg.Refill,
+		keys:       keys,
+		maxClients: cfg.MaxClients,
+		now:        time.Now,
+		buckets:    map[string]*bucket{},
+	}
+}
+
+// take refills the bucket of key for the time elapsed since its last use
+// and takes a token from it when there is one.
+func (l *RateLimiter) take(key string) rateDecision {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	b, ok := l.buckets[key]
+
+	if !ok {
+		if len(l.buckets) >= l.maxClients {
+			l.evict(now)
+		}
+
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = l.refilled(b, now)
+	b.updated = now
+
+	decision := rateDecision{allowed: b.tokens >= 1}
+
+	if decision.allowed {
+		b.tokens--
+	} else {
+		decision.retryAfter = l.duration(1 - b.tokens)
+	}
+
+	decision.remaining = int(b.tokens)
+	decision.reset = l.duration(l.burst - b.tokens)
+
+	return decision
+}
+
+func (l *RateLimiter) refilled(b *bucket, now time.Time) float64 {
+	return math.Min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.refill)
+}
+
+// duration is the time needed to regain tokens.
+func (l *RateLimiter) duration(tokens float64) time.Duration {
+	return time.Duration(tokens / l.refill * float64(time.Second))
+}
+
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < sweepInterval {
+		return
+	}
+
+	l.dropFull(now)
+	l.lastSweep = now
+}
+
+func (l *RateLimiter) dropFull(now time.Time) {
+	for key, b := range l.buckets {
+		if l.refilled(b, now) >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// evict makes room for a new client by dropping the buckets that refilled
+// completely or, when there are none, the one used least recently.
+func (l *RateLimiter) evict(now time.Time) {
+	l.dropFull(now)
+
+	if len(l.buckets) < l.maxClients {
+		return
+	}
+
+	var oldest string
+
+	for key, b := range l.buckets {
+		if oldest == "" || b.updated.Before(l.buckets[oldest].updated) {
+			oldest = key
+		}
+	}
+
+	delete(l.buckets, oldest)
+}
+
+// RateLimit rejects with a 429 the requests of clients that ran out of
+// tokens. Every response carries the RateLimit-Limit, RateLimit-Remaining
+// and RateLimit-Reset headers, and rejected ones a Retry-After header.
+func RateLimit(limiter *RateLimiter) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			decision := limiter.take(limiter.clientKey(r))
+
+			rw.Header().Set("RateLimit-Limit", strconv.Itoa(int(limiter.burst)))
+			rw.Header().Set("RateLimit-Remaining", strconv.Itoa(decision.remaining))
+			rw.Header().Set("RateLimit-Reset", seconds(decision.reset))
+
+			if !decision.allowed {
+				rw.Header().Set("Retry-After", seconds(decision.retryAfter))
+				httphelpers.Error(rw, errors.NewTooManyRequests())
+				return
+			}
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// clientKey identifies the caller by API key when it sends a configured one
+// and by IP address otherwise, so that made up keys cannot evade the limit.
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	if key := r.Header.Get(APIKeyHeader); l.keys[key] {
+		return "key:" + key
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return "ip:" + host
+}
+
+// seconds rounds d up to whole seconds, as rate limit headers expect.
+func seconds(d time.Duration) string {
+	return strconv.FormatInt(int64(math.Ceil(d.Seconds())), 10)
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/httphelpers"
+	"github.com/klasrak/go-meli-test-dojo/models"
+	"github.com/klasrak/go-meli-test-dojo/utils"
+)
+
+// renderOptions holds the query parameters that reshape a response.
+type renderOptions struct {
+	expand  []string
+	fields  []string
+	sort    []sortKey
+	filters []filter
+	typed   bool
+}
+
+// typedResources lists the resources with a typed view for ?format=typed.
+var typedResources = []string{"starships", "people"}
+
+// respond writes data as the response body, reshaped according to the
+// render options of the request and with upstream links pointing to us.
+func respond(rw http.ResponseWriter, r *http.Request, data interface{}) {
+	options, err := parseRenderOptions(r, data)
+
+	if err != nil {
+		httphelpers.BadRequest(rw, err)
+		return
+	}
+
+	_, list := resourceOf(data)
+
+	if len(options.sort) > 0 || len(options.filters) > 0 {
+		data = query(data, options.sort, options.filters)
+	}
+
+	modified := lastModified(data)
+
+	if options.typed {
+		data = typed(data)
+	}
+
+	var body interface{} = data
+
+	if len(options.expand) > 0 || len(options.fields) > 0 {
+		if body, err = render(r.Context(), data, list, options); err != nil {
+			httphelpers.InternalServerError(rw)
+			return
+		}
+	}
+
+	if !modified.IsZero() {
+		rw.Header().Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+
+	httphelpers.OK(rw, json.RawMessage(rewriteLinks(utils.ToJSON(body), publicBaseURL(r))))
+}
+
+func parseRenderOptions(r *http.Request, data interface{}) (options renderOptions, err error) {
+	resource, list := resourceOf(data)
+
+	if list {
+		if options.sort, options.filters, err = parseQuery(r, resource); err != nil {
+			return options, err
+		}
+	}
+
+	options.expand = listParam(r, "expand")
+
+	if unknown := unknownFields(options.expand, expandable[resource]); len(unknown) > 0 {
+		return options, errors.NewBadRequest(fmt.Sprintf("unknown expand fields: %s", strings.Join(unknown, ", ")))
+	}
+
+	options.fields = listParam(r, "fields")
+
+	if unknown := unknownFields(options.fields, selectable[resource]); len(unknown) > 0 {
+		return options, errors.NewBadRequest(fmt.Sprintf("unknown fields: %s", strings.Join(unknown, ", ")))
+	}
+
+	switch format := r.URL.Query().Get("format"); format {
+	case "":
+	case "typed":
+		if len(unknownFields([]string{resource}, typedResources)) > 0 {
+			return options, errors.NewBadRequest(fmt.Sprintf("format typed is not available for %s", resource))
+		}
+
+		options.typed = true
+	default:
+		return options, errors.NewBadRequest(fmt.Sprintf("invalid format: %s", format))
+	}
+
+	return options, nil
+}
+
+// typed returns the typed view of data when it has one.
+func typed(data interface{}) interface{} {
+	switch v := data.(type) {
+	case models.Starship:
+		return v.Typed()
+	case models.Starships:
+		return v.Typed()
+	case models.People:
+		return v.Typed()
+	case models.PeopleList:
+		return v.Typed()
+	default:
+		return data
+	}
+}
+
+// render turns data into an Object and applies options to it, or to each
+// of its results when data is a list.
+func render(ctx context.Context, data interface{}, list bool, options renderOptions) (*utils.Object, error) {
+	root, err := utils.ToObject(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	items := []*utils.Object{root}
+
+	if list {
+		if items, err = listItems(root); err != nil {
+			return nil, err
+		}
+	}
+
+	// Fields dropped by ?fields= are not expanded, so that their
+	// unresolved links are not reported either.
+	expanded := options.expand
+
+	if len(options.fields) > 0 {
+		expanded = []string{}
+
+		for _, field := range options.expand {
+			if len(unknownFields([]string{field}, options.fields)) == 0 {
+				expanded = append(expanded, field)
+			}
+		}
+	}
+
+	if len(expanded) > 0 {
+		if err := expand(ctx, items, expanded); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(options.fields) > 0 {
+		selectFields(items, options.fields)
+	}
+
+	if list {
+		if err := root.Set("results", items); err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
+}
+
+// listItems decodes the results of a list response into Objects.
+func listItems(root *utils.Object) ([]*utils.Object, error) {
+	items := []*utils.Object{}
+	raw, ok := root.Get("results")
+
+	if !ok {
+		return items, nil
+	}
+
+	var results []json.RawMessage
+
+	if err := json.Unmarshal(raw, &results); err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		item, err := utils.ParseObject(result)
+
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+// resourceOf returns the SWAPI resource name of data and whether it is a
+// list of that resource.
+func resourceOf(data interface{}) (string, bool) {
+	switch data.(type) {
+	case models.Starship:
+		return "starships", false
+	case models.Starships:
+		return "starships", true
+	case models.People:
+		return "people", false
+	case models.PeopleList:
+		return "people", true
+	case models.Film:
+		return "films", false
+	case models.Films:
+		return "films", true
+	case models.Planet:
+		return "planets", false
+	case models.Planets:
+		return "planets", true
+	case models.Species:
+		return "species", false
+	case models.SpeciesList:
+		return "species", true
+	case models.Vehicle:
+		return "vehicles", false
+	case models.Vehicles:
+		return "vehicles", true
+	default:
+		return "", false
+	}
+}
+
+// lastModified is the latest edited time of data, or the zero time when
+// the resource does not track it.
+func lastModified(data interface{}) (modified time.Time) {
+	var edited []time.Time
+
+	switch v := data.(type) {
+	case models.Starship:
+		edited = append(edited, v.Edited)
+	case models.Starships:
+		for _, item := range v.Results {
+			edited = append(edited, item.Edited)
+		}
+	case models.People:
+		edited = append(edited, v.Edited)
+	case models.PeopleList:
+		for _, item := range v.Results {
+			edited = append(edited, item.Edited)
+		}
+	}
+
+	for _, t := range edited {
+		if t.After(modified) {
+			modified = t
+		}
+	}
+
+	return modified
+}
+
+// listParam splits a comma separated query parameter, dropping blanks.
+func listParam(r *http.Request, name string) []string {
+	values := []string{}
+
+	for _, value := range strings.Split(r.URL.Query().Get(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// unknownFields returns the fields that are not in allowed.
+func unknownFields(fields []string, allowed []string) []string {
+	unknown := []string{}
+
+	for _, field := range fields {
+		found := false
+
+		for _, a := range allowed {
+			if field == a {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			unknown = append(unknown, field)
+		}
+	}
+
+	return unknown
+}
@@ -1,12 +1,37 @@
 package api
 
-import "github.com/go-chi/chi/v5"
+import (
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// URLMapping registers every route on router, logging requests to logOutput.
+// The middlewares apply only to the /api/v1 routes, leaving health checks and
+// metrics untouched.
+func URLMapping(router *chi.Mux, logOutput io.Writer, middlewares ...func(http.Handler) http.Handler) {
+	router.Use(RequestID, Logger(logOutput), Metrics, NoCache)
+
+	router.Get("/health", HealthHandler)
+	router.Get("/ready", ReadyHandler)
+	router.Get("/metrics", MetricsHandler)
 
-func URLMapping(router *chi.Mux) {
 	router.Route("/api/v1", func(r chi.Router) {
+		r.Use(middlewares...)
+
 		r.Get("/starships/{id}", GetStarshipHandler)
 		r.Get("/starships", GetStarshipsHandler)
 		r.Get("/people/{id}", GetPeopleHandler)
+		r.Get("/people/{id}/homeworld", GetPeopleHomeworldHandler)
 		r.Get("/people", GetPeopleListHandler)
+		r.Get("/films/{id}", GetFilmHandler)
+		r.Get("/films", GetFilmsHandler)
+		r.Get("/planets/{id}", GetPlanetHandler)
+		r.Get("/planets", GetPlanetsHandler)
+		r.Get("/species/{id}", GetSpeciesHandler)
+		r.Get("/species", GetSpeciesListHandler)
+		r.Get("/vehicles/{id}", GetVehicleHandler)
+		r.Get("/vehicles", GetVehiclesHandler)
 	})
 }
@@ -26,7 +26,7 @@ func (response *Response) StringBody() string {
 func GetTestRouter() *chi.Mux {
 	router := chi.NewRouter()
 
-	URLMapping(router)
+	URLMapping(router, io.Discard)
 
 	return router
 }
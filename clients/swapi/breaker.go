@@ -0,0 +1,269 @@
+package swapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/models"
+)
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	StateClosed BreakerState = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig configures a CircuitBreaker. The breaker opens after
+// FailureThreshold consecutive failures, stays open for CoolDown and then
+// lets a single probe through; SuccessThreshold successful probes close it
+// again.
+type BreakerConfig struct {
+	FailureThreshold int
+	SuccessThreshold int
+	CoolDown         time.Duration
+}
+
+var DefaultBreakerConfig = BreakerConfig{
+	FailureThreshold: 5,
+	SuccessThreshold: 1,
+	CoolDown:         30 * time.Second,
+}
+
+// CircuitBreaker is a Client decorator that fails fast with an
+// errors.Unavailable error while the upstream API is failing.
+type CircuitBreaker struct {
+	client Client
+	config BreakerConfig
+	now    func() time.Time
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	successes int
+	openedAt  time.Time
+	probing   bool
+}
+
+func NewCircuitBreaker(client Client, config BreakerConfig) *CircuitBreaker {
+	if config.FailureThreshold < 1 {
+		config.FailureThreshold = 1
+	}
+
+	if config.SuccessThreshold < 1 {
+		config.SuccessThreshold = 1
+	}
+
+	return &CircuitBreaker{
+		client: client,
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// State returns the current state, moving an open breaker to half-open once
+// its cool-down is over.
+func (cb *CircuitBreaker) State() BreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refresh()
+
+	return cb.state
+}
+
+// guard runs fn unless the breaker is open and records its outcome.
+func guard[T any](cb *CircuitBreaker, fn func() (T, error)) (T, error) {
+	if !cb.allow() {
+		var zero T
+		return zero, errors.NewUnavailable()
+	}
+
+	result, err := fn()
+	cb.record(err)
+
+	return result, err
+}
+
+func (cb *CircuitBreaker) allow() bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refresh()
+
+	switch cb.state {
+	case StateOpen:
+		return false
+	case StateHalfOpen:
+		if cb.probing {
+			return false
+		}
+
+		cb.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (cb *CircuitBreaker) record(err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	// A caller giving up says nothing about the upstream health.
+	if errors.Status(err) == errors.StatusClientClosedRequest {
+		cb.probing = false
+		return
+	}
+
+	failed := isUpstreamFailure(err)
+
+	if cb.state == StateHalfOpen {
+		cb.probing = false
+
+		if failed {
+			cb.open()
+			return
+		}
+
+		cb.successes++
+
+		if cb.successes >= cb.config.SuccessThreshold {
+			cb.state = StateClosed
+			cb.failures = 0
+		}
+
+		return
+	}
+
+	if !failed {
+		cb.failures = 0
+		return
+	}
+
+	cb.failures++
+
+	if cb.failures >= cb.config.FailureThreshold {
+		cb.open()
+	}
+}
+
+func (cb *CircuitBreaker) open() {
+	cb.state = StateOpen
+	cb.openedAt = cb.now()
+	cb.failures = 0
+	cb.successes = 0
+}
+
+func (cb *CircuitBreaker) refresh() {
+	if cb.state == StateOpen && cb.now().Sub(cb.openedAt) >= cb.config.CoolDown {
+		cb.state = StateHalfOpen
+		cb.successes = 0
+		cb.probing = false
+	}
+}
+
+// isUpstreamFailure tells whether err means the upstream API misbehaved.
+// Missing resources and bad input do not count.
+func isUpstreamFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch errors.Status(err) {
+	case http.StatusNotFound, http.StatusBadRequest:
+		return false
+	default:
+		return true
+	}
+}
+
+func (cb *CircuitBreaker) GetStarship(ctx context.Context, id int) (models.Starship, error) {
+	return guard(cb, func() (models.Starship, error) {
+		return cb.client.GetStarship(ctx, id)
+	})
+}
+
+func (cb *CircuitBreaker) GetStarships(ctx context.Context, page int, search string) (models.Starships, error) {
+	return guard(cb, func() (models.Starships, error) {
+		return cb.client.GetStarships(ctx, page, search)
+	})
+}
+
+func (cb *CircuitBreaker) GetPeople(ctx context.Context, id int) (models.People, error) {
+	return guard(cb, func() (models.People, error) {
+		return cb.client.GetPeople(ctx, id)
+	})
+}
+
+func (cb *CircuitBreaker) GetPeopleList(ctx context.Context, page int, search string) (models.PeopleList, error) {
+	return guard(cb, func() (models.PeopleList, error) {
+		return cb.client.GetPeopleList(ctx, page, search)
+	})
+}
+
+func (cb *CircuitBreaker) GetFilm(ctx context.Context, id int) (models.Film, error) {
+	return guard(cb, func() (models.Film, error) {
+		return cb.client.GetFilm(ctx, id)
+	})
+}
+
+func (cb *CircuitBreaker) GetFilms(ctx context.Context, page int, search string) (models.Films, error) {
+	return guard(cb, func() (models.Films, error) {
+		return cb.client.GetFilms(ctx, page, search)
+	})
+}
+
+func (cb *CircuitBreaker) GetPlanet(ctx context.Context, id int) (models.Planet, error) {
+	return guard(cb, func() (models.Planet, error) {
+		return cb.client.GetPlanet(ctx, id)
+	})
+}
+
+func (cb *CircuitBreaker) GetPlanets(ctx context.Context, page int, search string) (models.Planets, error) {
+	return guard(cb, func() (models.Planets, error) {
+		return cb.client.GetPlanets(ctx, page, search)
+	})
+}
+
+func (cb *CircuitBreaker) GetSpecies(ctx context.Context, id int) (models.Species, error) {
+	return guard(cb, func() (models.Species, error) {
+		return cb.client.GetSpecies(ctx, id)
+	})
+}
+
+func (cb *CircuitBreaker) GetSpeciesList(ctx context.Context, page int, search string) (models.SpeciesList, error) {
+	return guard(cb, func() (models.SpeciesList, error) {
+		return cb.client.GetSpeciesList(ctx, page, search)
+	})
+}
+
+func (cb *CircuitBreaker) GetVehicle(ctx context.Context, id int) (models.Vehicle, error) {
+	return guard(cb, func() (models.Vehicle, error) {
+		return cb.client.GetVehicle(ctx, id)
+	})
+}
+
+func (cb *CircuitBreaker) GetVehicles(ctx context.Context, page int, search string) (models.Vehicles, error) {
+	return guard(cb, func() (models.Vehicles, error) {
+		return cb.client.GetVehicles(ctx, page, search)
+	})
+}
@@ -0,0 +1,131 @@
+package swapi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/mockeable"
+	"github.com/klasrak/go-meli-test-dojo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestBreaker(client Client, clock *fakeClock) *CircuitBreaker {
+	breaker := NewCircuitBreaker(client, BreakerConfig{FailureThreshold: 2, SuccessThreshold: 1, CoolDown: time.Minute})
+	breaker.now = clock.Now
+
+	return breaker
+}
+
+func TestCircuitBreakerOpensAfterFailures(t *testing.T) {
+	mock := MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{}, errors.NewInternal()
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	breaker := newTestBreaker(&mock, &fakeClock{now: time.Now()})
+
+	for i := 0; i < 2; i++ {
+		_, err := breaker.GetStarship(context.Background(), 9)
+		assert.Equal(t, http.StatusInternalServerError, errors.Status(err))
+	}
+
+	assert.Equal(t, StateOpen, breaker.State())
+
+	_, err := breaker.GetStarship(context.Background(), 9)
+	assert.Equal(t, http.StatusServiceUnavailable, errors.Status(err))
+}
+
+func TestCircuitBreakerIgnoresNotFound(t *testing.T) {
+	mock := MockClient{
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			return models.People{}, errors.NewNotFound("people", "99")
+		},
+		GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 3},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	breaker := newTestBreaker(&mock, &fakeClock{now: time.Now()})
+
+	for i := 0; i < 3; i++ {
+		_, err := breaker.GetPeople(context.Background(), 99)
+		assert.Equal(t, http.StatusNotFound, errors.Status(err))
+	}
+
+	assert.Equal(t, StateClosed, breaker.State())
+}
+
+func TestCircuitBreakerHalfOpenRecovers(t *testing.T) {
+	failing := true
+
+	mock := MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			if failing {
+				return models.Starship{}, errors.NewTimeout()
+			}
+
+			return models.Starship{Name: "Death Star"}, nil
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 3},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	clock := &fakeClock{now: time.Now()}
+	breaker := newTestBreaker(&mock, clock)
+
+	breaker.GetStarship(context.Background(), 9)
+	breaker.GetStarship(context.Background(), 9)
+	assert.Equal(t, StateOpen, breaker.State())
+
+	clock.now = clock.now.Add(time.Minute)
+	assert.Equal(t, StateHalfOpen, breaker.State())
+
+	failing = false
+	result, err := breaker.GetStarship(context.Background(), 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Death Star", result.Name)
+	assert.Equal(t, StateClosed, breaker.State())
+}
+
+func TestCircuitBreakerHalfOpenFailureReopens(t *testing.T) {
+	mock := MockClient{
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
+			return models.Starships{}, errors.NewInternal()
+		},
+		GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 3},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	clock := &fakeClock{now: time.Now()}
+	breaker := newTestBreaker(&mock, clock)
+
+	breaker.GetStarships(context.Background(), 0, "")
+	breaker.GetStarships(context.Background(), 0, "")
+
+	clock.now = clock.now.Add(time.Minute)
+	breaker.GetStarships(context.Background(), 0, "")
+
+	assert.Equal(t, StateOpen, breaker.State())
+}
@@ -0,0 +1,239 @@
+package swapi
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/models"
+)
+
+// CacheConfig configures a Cache. TTL overrides DefaultTTL per resource name
+// ("starships", "people", "films", "planets", "species" and "vehicles").
+type CacheConfig struct {
+	MaxEntries int
+	DefaultTTL time.Duration
+	TTL        map[string]time.Duration
+}
+
+var DefaultCacheConfig = CacheConfig{
+	MaxEntries: 1000,
+	DefaultTTL: time.Hour,
+}
+
+// CacheStats holds the hit and miss counters of a Cache.
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// Cache is a Client decorator that keeps successful responses in memory in
+// a size bounded LRU. Errors are never cached.
+type Cache struct {
+	client Client
+	config CacheConfig
+	now    func() time.Time
+
+	hits   uint64
+	misses uint64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+}
+
+type cacheEntry struct {
+	key       string
+	value     interface{}
+	expiresAt time.Time
+}
+
+type noCacheKey struct{}
+
+// WithoutCache marks ctx so that Cache skips cached entries for the calls
+// made with it. Fresh results are still stored.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(noCacheKey{}).(bool)
+	return bypass
+}
+
+func NewCache(client Client, config CacheConfig) *Cache {
+	return &Cache{
+		client:  client,
+		config:  config,
+		now:     time.Now,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+func (c *Cache) Stats() CacheStats {
+	return CacheStats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+	}
+}
+
+// cached returns the entry stored for resource and key, calling fn and
+// storing its result when there is no fresh entry.
+func cached[T any](ctx context.Context, c *Cache, resource string, key string, fn func() (T, error)) (T, error) {
+	key = resource + "/" + key
+
+	if !cacheBypassed(ctx) {
+		if value, ok := c.lookup(key); ok {
+			atomic.AddUint64(&c.hits, 1)
+			return value.(T), nil
+		}
+	}
+
+	atomic.AddUint64(&c.misses, 1)
+
+	result, err := fn()
+
+	if err == nil {
+		c.store(key, result, c.ttl(resource))
+	}
+
+	return result, err
+}
+
+func (c *Cache) ttl(resource string) time.Duration {
+	if ttl, ok := c.config.TTL[resource]; ok {
+		return ttl
+	}
+
+	return c.config.DefaultTTL
+}
+
+func (c *Cache) lookup(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*cacheEntry)
+
+	if !c.now().Before(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return entry.value, true
+}
+
+func (c *Cache) store(key string, value interface{}, ttl time.Duration) {
+	if ttl <= 0 || c.config.MaxEntries <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &cacheEntry{key: key, value: value, expiresAt: c.now().Add(ttl)}
+
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+
+	for c.order.Len() > c.config.MaxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func listKey(page int, search string) string {
+	return fmt.Sprintf("list?page=%d&search=%s", page, search)
+}
+
+func (c *Cache) GetStarship(ctx context.Context, id int) (models.Starship, error) {
+	return cached(ctx, c, "starships", fmt.Sprintf("%d", id), func() (models.Starship, error) {
+		return c.client.GetStarship(ctx, id)
+	})
+}
+
+func (c *Cache) GetStarships(ctx context.Context, page int, search string) (models.Starships, error) {
+	return cached(ctx, c, "starships", listKey(page, search), func() (models.Starships, error) {
+		return c.client.GetStarships(ctx, page, search)
+	})
+}
+
+func (c *Cache) GetPeople(ctx context.Context, id int) (models.People, error) {
+	return cached(ctx, c, "people", fmt.Sprintf("%d", id), func() (models.People, error) {
+		return c.client.GetPeople(ctx, id)
+	})
+}
+
+func (c *Cache) GetPeopleList(ctx context.Context, page int, search string) (models.PeopleList, error) {
+	return cached(ctx, c, "people", listKey(page, search), func() (models.PeopleList, error) {
+		return c.client.GetPeopleList(ctx, page, search)
+	})
+}
+
+func (c *Cache) GetFilm(ctx context.Context, id int) (models.Film, error) {
+	return cached(ctx, c, "films", fmt.Sprintf("%d", id), func() (models.Film, error) {
+		return c.client.GetFilm(ctx, id)
+	})
+}
+
+func (c *Cache) GetFilms(ctx context.Context, page int, search string) (models.Films, error) {
+	return cached(ctx, c, "films", listKey(page, search), func() (models.Films, error) {
+		return c.client.GetFilms(ctx, page, search)
+	})
+}
+
+func (c *Cache) GetPlanet(ctx context.Context, id int) (models.Planet, error) {
+	return cached(ctx, c, "planets", fmt.Sprintf("%d", id), func() (models.Planet, error) {
+		return c.client.GetPlanet(ctx, id)
+	})
+}
+
+func (c *Cache) GetPlanets(ctx context.Context, page int, search string) (models.Planets, error) {
+	return cached(ctx, c, "planets", listKey(page, search), func() (models.Planets, error) {
+		return c.client.GetPlanets(ctx, page, search)
+	})
+}
+
+func (c *Cache) GetSpecies(ctx context.Context, id int) (models.Species, error) {
+	return cached(ctx, c, "species", fmt.Sprintf("%d", id), func() (models.Species, error) {
+		return c.client.GetSpecies(ctx, id)
+	})
+}
+
+func (c *Cache) GetSpeciesList(ctx context.Context, page int, search string) (models.SpeciesList, error) {
+	return cached(ctx, c, "species", listKey(page, search), func() (models.SpeciesList, error) {
+		return c.client.GetSpeciesList(ctx, page, search)
+	})
+}
+
+func (c *Cache) GetVehicle(ctx context.Context, id int) (models.Vehicle, error) {
+	return cached(ctx, c, "vehicles", fmt.Sprintf("%d", id), func() (models.Vehicle, error) {
+		return c.client.GetVehicle(ctx, id)
+	})
+}
+
+func (c *Cache) GetVehicles(ctx context.Context, page int, search string) (models.Vehicles, error) {
+	return cached(ctx, c, "vehicles", listKey(page, search), func() (models.Vehicles, error) {
+		return c.client.GetVehicles(ctx, page, search)
+	})
+}
@@ -0,0 +1,130 @@
+package swapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/mockeable"
+	"github.com/klasrak/go-meli-test-dojo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheHit(t *testing.T) {
+	mock := MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{Name: "Death Star"}, nil
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	cache := NewCache(&mock, DefaultCacheConfig)
+
+	for i := 0; i < 3; i++ {
+		result, err := cache.GetStarship(context.Background(), 9)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Death Star", result.Name)
+	}
+
+	assert.Equal(t, CacheStats{Hits: 2, Misses: 1}, cache.Stats())
+}
+
+func TestCacheExpiresPerResource(t *testing.T) {
+	mock := MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			return models.Starship{Name: "Death Star"}, nil
+		},
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			return models.People{Name: "Luke Skywalker"}, nil
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+		GetPeopleFuncControl:   mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	clock := &fakeClock{now: time.Now()}
+	cache := NewCache(&mock, CacheConfig{
+		MaxEntries: 10,
+		DefaultTTL: time.Hour,
+		TTL:        map[string]time.Duration{"starships": time.Minute},
+	})
+	cache.now = clock.Now
+
+	cache.GetStarship(context.Background(), 9)
+	cache.GetPeople(context.Background(), 1)
+
+	clock.now = clock.now.Add(2 * time.Minute)
+
+	cache.GetStarship(context.Background(), 9)
+	cache.GetPeople(context.Background(), 1)
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	calls := map[int]int{}
+
+	mock := MockClient{
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			calls[id]++
+			return models.People{}, nil
+		},
+		GetPeopleFuncControl: mockeable.CallsFuncControl{IgnoreCallsAssertion: true},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	cache := NewCache(&mock, CacheConfig{MaxEntries: 2, DefaultTTL: time.Hour})
+
+	cache.GetPeople(context.Background(), 1)
+	cache.GetPeople(context.Background(), 2)
+	cache.GetPeople(context.Background(), 1)
+	cache.GetPeople(context.Background(), 3)
+	cache.GetPeople(context.Background(), 1)
+	cache.GetPeople(context.Background(), 2)
+
+	assert.Equal(t, map[int]int{1: 1, 2: 2, 3: 1}, calls)
+}
+
+func TestCacheSkipsErrors(t *testing.T) {
+	mock := MockClient{
+		GetStarshipsFunc: func(ctx context.Context, page int, search string) (models.Starships, error) {
+			return models.Starships{}, errors.NewInternal()
+		},
+		GetStarshipsFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	cache := NewCache(&mock, DefaultCacheConfig)
+
+	cache.GetStarships(context.Background(), 0, "")
+	cache.GetStarships(context.Background(), 0, "")
+}
+
+func TestCacheBypass(t *testing.T) {
+	mock := MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			return models.Film{Title: "A New Hope"}, nil
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	cache := NewCache(&mock, DefaultCacheConfig)
+
+	cache.GetFilm(context.Background(), 1)
+	cache.GetFilm(WithoutCache(context.Background()), 1)
+	cache.GetFilm(context.Background(), 1)
+
+	assert.Equal(t, CacheStats{Hits: 1, Misses: 2}, cache.Stats())
+}
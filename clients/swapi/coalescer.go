@@ -0,0 +1,219 @@
+package swapi
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/models"
+)
+
+// Coalescer is a Client decorator that collapses concurrent identical
+// lookups: callers asking for the same resource while a request for it is in
+// flight share that request and its result or error.
+type Coalescer struct {
+	client Client
+
+	mu    sync.Mutex
+	calls map[string]*inflight
+}
+
+type inflight struct {
+	done    chan struct{}
+	cancel  context.CancelFunc
+	waiters int
+	value   interface{}
+	err     error
+}
+
+func NewCoalescer(client Client) *Coalescer {
+	return &Coalescer{
+		client: client,
+		calls:  make(map[string]*inflight),
+	}
+}
+
+// coalesce runs fn once per key among concurrent callers. The shared call is
+// detached from the cancellation of the caller that started it so that one
+// caller giving up does not fail the others. Every caller stops waiting as
+// soon as its own ctx is done, and the shared call is canceled once no
+// caller is left waiting for it.
+func coalesce[T any](ctx context.Context, c *Coalescer, key string, fn func(ctx context.Context) (T, error)) (T, error) {
+	c.mu.Lock()
+
+	call, ok := c.calls[key]
+
+	if !ok {
+		shared, cancel := context.WithCancel(detachedContext{parent: ctx})
+		call = &inflight{done: make(chan struct{}), cancel: cancel}
+		c.calls[key] = call
+
+		go func() {
+			defer cancel()
+
+			value, err := fn(shared)
+
+			c.mu.Lock()
+			call.value, call.err = value, err
+
+			if c.calls[key] == call {
+				delete(c.calls, key)
+			}
+
+			c.mu.Unlock()
+
+			close(call.done)
+		}()
+	}
+
+	call.waiters++
+	c.mu.Unlock()
+
+	select {
+	case <-call.done:
+		c.leave(key, call)
+
+		if call.err != nil {
+			var zero T
+			return zero, call.err
+		}
+
+		return call.value.(T), nil
+	case <-ctx.Done():
+		c.leave(key, call)
+
+		var zero T
+		return zero, transportError(ctx, ctx.Err())
+	}
+}
+
+// leave removes a caller from call, canceling it when it was the last one
+// and the call is still running. Later callers then start a new call instead
+// of joining the canceled one.
+func (c *Coalescer) leave(key string, call *inflight) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call.waiters--
+
+	if call.waiters > 0 {
+		return
+	}
+
+	select {
+	case <-call.done:
+	default:
+		call.cancel()
+
+		if c.calls[key] == call {
+			delete(c.calls, key)
+		}
+	}
+}
+
+// waiters reports how many callers are waiting for the in-flight call for
+// key.
+func (c *Coalescer) waiters(key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if call, ok := c.calls[key]; ok {
+		return call.waiters
+	}
+
+	return 0
+}
+
+// detachedContext keeps the values of its parent but is never canceled.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (d detachedContext) Value(key interface{}) interface{} {
+	return d.parent.Value(key)
+}
+
+func (c *Coalescer) GetStarship(ctx context.Context, id int) (models.Starship, error) {
+	return coalesce(ctx, c, "starships/"+strconv.Itoa(id), func(ctx context.Context) (models.Starship, error) {
+		return c.client.GetStarship(ctx, id)
+	})
+}
+
+func (c *Coalescer) GetStarships(ctx context.Context, page int, search string) (models.Starships, error) {
+	return coalesce(ctx, c, "starships/"+listKey(page, search), func(ctx context.Context) (models.Starships, error) {
+		return c.client.GetStarships(ctx, page, search)
+	})
+}
+
+func (c *Coalescer) GetPeople(ctx context.Context, id int) (models.People, error) {
+	return coalesce(ctx, c, "people/"+strconv.Itoa(id), func(ctx context.Context) (models.People, error) {
+		return c.client.GetPeople(ctx, id)
+	})
+}
+
+func (c *Coalescer) GetPeopleList(ctx context.Context, page int, search string) (models.PeopleList, error) {
+	return coalesce(ctx, c, "people/"+listKey(page, search), func(ctx context.Context) (models.PeopleList, error) {
+		return c.client.GetPeopleList(ctx, page, search)
+	})
+}
+
+func (c *Coalescer) GetFilm(ctx context.Context, id int) (models.Film, error) {
+	return coalesce(ctx, c, "films/"+strconv.Itoa(id), func(ctx context.Context) (models.Film, error) {
+		return c.client.GetFilm(ctx, id)
+	})
+}
+
+func (c *Coalescer) GetFilms(ctx context.Context, page int, search string) (models.Films, error) {
+	return coalesce(ctx, c, "films/"+listKey(page, search), func(ctx context.Context) (models.Films, error) {
+		return c.client.GetFilms(ctx, page, search)
+	})
+}
+
+func (c *Coalescer) GetPlanet(ctx context.Context, id int) (models.Planet, error) {
+	return coalesce(ctx, c, "planets/"+strconv.Itoa(id), func(ctx context.Context) (models.Planet, error) {
+		return c.client.GetPlanet(ctx, id)
+	})
+}
+
+func (c *Coalescer) GetPlanets(ctx context.Context, page int, search string) (models.Planets, error) {
+	return coalesce(ctx, c, "planets/"+listKey(page, search), func(ctx context.Context) (models.Planets, error) {
+		return c.client.GetPlanets(ctx, page, search)
+	})
+}
+
+func (c *Coalescer) GetSpecies(ctx context.Context, id int) (models.Species, error) {
+	return coalesce(ctx, c, "species/"+strconv.Itoa(id), func(ctx context.Context) (models.Species, error) {
+		return c.client.GetSpecies(ctx, id)
+	})
+}
+
+func (c *Coalescer) GetSpeciesList(ctx context.Context, page int, search string) (models.SpeciesList, error) {
+	return coalesce(ctx, c, "species/"+listKey(page, search), func(ctx context.Context) (models.SpeciesList, error) {
+		return c.client.GetSpeciesList(ctx, page, search)
+	})
+}
+
+func (c *Coalescer) GetVehicle(ctx context.Context, id int) (models.Vehicle, error) {
+	return coalesce(ctx, c, "vehicles/"+strconv.Itoa(id), func(ctx context.Context) (models.Vehicle, error) {
+		return c.client.GetVehicle(ctx, id)
+	})
+}
+
+func (c *Coalescer) GetVehicles(ctx context.Context, page int, search string) (models.Vehicles, error) {
+	return coalesce(ctx, c, "vehicles/"+listKey(page, search), func(ctx context.Context) (models.Vehicles, error) {
+		return c.client.GetVehicles(ctx, page, search)
+	})
+}
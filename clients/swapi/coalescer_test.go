@@ -0,0 +1,194 @@
+package swapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/mockeable"
+	"github.com/klasrak/go-meli-test-dojo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForWaiters blocks until n callers wait for the in-flight call for key.
+func waitForWaiters(t *testing.T, coalescer *Coalescer, key string, n int) {
+	deadline := time.Now().Add(time.Second)
+
+	for coalescer.waiters(key) < n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d waiters for %s, got %d", n, key, coalescer.waiters(key))
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCoalescerSharesInFlightCall(t *testing.T) {
+	const callers = 50
+
+	release := make(chan struct{})
+
+	mock := MockClient{
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			<-release
+			return models.People{Name: "Luke Skywalker"}, nil
+		},
+		GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	coalescer := NewCoalescer(&mock)
+	results := make([]models.People, callers)
+	errs := make([]error, callers)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			results[i], errs[i] = coalescer.GetPeople(context.Background(), 1)
+		}(i)
+	}
+
+	waitForWaiters(t, coalescer, "people/1", callers)
+	close(release)
+	wg.Wait()
+
+	for i := 0; i < callers; i++ {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, "Luke Skywalker", results[i].Name)
+	}
+}
+
+func TestCoalescerSharesErrors(t *testing.T) {
+	release := make(chan struct{})
+
+	mock := MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			<-release
+			return models.Starship{}, errors.NewNotFound("starships", "99")
+		},
+		GetStarshipFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	coalescer := NewCoalescer(&mock)
+	errs := make([]error, 2)
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = coalescer.GetStarship(context.Background(), 99)
+		}(i)
+	}
+
+	waitForWaiters(t, coalescer, "starships/99", 2)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, http.StatusNotFound, errors.Status(errs[0]))
+	assert.Equal(t, http.StatusNotFound, errors.Status(errs[1]))
+}
+
+func TestCoalescerKeepsDistinctKeysApart(t *testing.T) {
+	mock := MockClient{
+		GetPeopleFunc: func(ctx context.Context, id int) (models.People, error) {
+			return models.People{}, nil
+		},
+		GetPeopleFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 2},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	coalescer := NewCoalescer(&mock)
+
+	coalescer.GetPeople(context.Background(), 1)
+	coalescer.GetPeople(context.Background(), 2)
+}
+
+func TestCoalescerCancelsWhenLastCallerLeaves(t *testing.T) {
+	canceled := make(chan error, 1)
+
+	mock := MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			<-ctx.Done()
+			canceled <- ctx.Err()
+			return models.Film{}, ctx.Err()
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	coalescer := NewCoalescer(&mock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := coalescer.GetFilm(ctx, 1)
+
+	assert.Equal(t, errors.StatusClientClosedRequest, errors.Status(err))
+	assert.Equal(t, context.Canceled, <-canceled)
+	assert.Equal(t, 0, coalescer.waiters("films/1"))
+}
+
+func TestCoalescerKeepsCallForRemainingCallers(t *testing.T) {
+	release := make(chan struct{})
+	sharedErr := make(chan error, 1)
+
+	mock := MockClient{
+		GetFilmFunc: func(ctx context.Context, id int) (models.Film, error) {
+			<-release
+			sharedErr <- ctx.Err()
+			return models.Film{Title: "A New Hope"}, nil
+		},
+		GetFilmFuncControl: mockeable.CallsFuncControl{ExpectedCalls: 1},
+	}
+
+	mock.Use()
+	defer mockeable.CleanUpAndAssertControls(t, &mock)
+
+	coalescer := NewCoalescer(&mock)
+	ctx, cancel := context.WithCancel(context.Background())
+	leaving := make(chan error, 1)
+
+	go func() {
+		_, err := coalescer.GetFilm(ctx, 1)
+		leaving <- err
+	}()
+
+	waitForWaiters(t, coalescer, "films/1", 1)
+
+	staying := make(chan models.Film, 1)
+
+	go func() {
+		film, _ := coalescer.GetFilm(context.Background(), 1)
+		staying <- film
+	}()
+
+	waitForWaiters(t, coalescer, "films/1", 2)
+	cancel()
+
+	assert.Equal(t, errors.StatusClientClosedRequest, errors.Status(<-leaving))
+	waitForWaiters(t, coalescer, "films/1", 1)
+	close(release)
+
+	assert.Equal(t, "A New Hope", (<-staying).Title)
+	assert.NoError(t, <-sharedErr)
+	assert.Equal(t, 0, coalescer.waiters("films/1"))
+}
@@ -1,12 +1,24 @@
 package swapi
 
-import "github.com/klasrak/go-meli-test-dojo/models"
+import (
+	"context"
+
+	"github.com/klasrak/go-meli-test-dojo/models"
+)
 
 type Client interface {
-	GetStarship(id int) (models.Starship, error)
-	GetStarships() (models.Starships, error)
-	GetPeople(id int) (models.People, error)
-	GetPeopleList() (models.PeopleList, error)
+	GetStarship(ctx context.Context, id int) (models.Starship, error)
+	GetStarships(ctx context.Context, page int, search string) (models.Starships, error)
+	GetPeople(ctx context.Context, id int) (models.People, error)
+	GetPeopleList(ctx context.Context, page int, search string) (models.PeopleList, error)
+	GetFilm(ctx context.Context, id int) (models.Film, error)
+	GetFilms(ctx context.Context, page int, search string) (models.Films, error)
+	GetPlanet(ctx context.Context, id int) (models.Planet, error)
+	GetPlanets(ctx context.Context, page int, search string) (models.Planets, error)
+	GetSpecies(ctx context.Context, id int) (models.Species, error)
+	GetSpeciesList(ctx context.Context, page int, search string) (models.SpeciesList, error)
+	GetVehicle(ctx context.Context, id int) (models.Vehicle, error)
+	GetVehicles(ctx context.Context, page int, search string) (models.Vehicles, error)
 }
 
 var (
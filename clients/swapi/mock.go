@@ -1,44 +1,109 @@
 package swapi
 
 import (
+	"context"
 	"github.com/klasrak/go-meli-test-dojo/mockeable"
 	"github.com/klasrak/go-meli-test-dojo/models"
 )
 
 type MockClient struct {
-	GetStarshipFunc   func(id int) (models.Starship, error)
-	GetStarshipsFunc  func() (models.Starships, error)
-	GetPeopleFunc     func(id int) (models.People, error)
-	GetPeopleListFunc func() (models.PeopleList, error)
+	GetStarshipFunc    func(ctx context.Context, id int) (models.Starship, error)
+	GetStarshipsFunc   func(ctx context.Context, page int, search string) (models.Starships, error)
+	GetPeopleFunc      func(ctx context.Context, id int) (models.People, error)
+	GetPeopleListFunc  func(ctx context.Context, page int, search string) (models.PeopleList, error)
+	GetFilmFunc        func(ctx context.Context, id int) (models.Film, error)
+	GetFilmsFunc       func(ctx context.Context, page int, search string) (models.Films, error)
+	GetPlanetFunc      func(ctx context.Context, id int) (models.Planet, error)
+	GetPlanetsFunc     func(ctx context.Context, page int, search string) (models.Planets, error)
+	GetSpeciesFunc     func(ctx context.Context, id int) (models.Species, error)
+	GetSpeciesListFunc func(ctx context.Context, page int, search string) (models.SpeciesList, error)
+	GetVehicleFunc     func(ctx context.Context, id int) (models.Vehicle, error)
+	GetVehiclesFunc    func(ctx context.Context, page int, search string) (models.Vehicles, error)
 
-	GetStarshipFuncControl   mockeable.CallsFuncControl
-	GetStarshipsFuncControl  mockeable.CallsFuncControl
-	GetPeopleFuncControl     mockeable.CallsFuncControl
-	GetPeopleListFuncControl mockeable.CallsFuncControl
+	GetStarshipFuncControl    mockeable.CallsFuncControl
+	GetStarshipsFuncControl   mockeable.CallsFuncControl
+	GetPeopleFuncControl      mockeable.CallsFuncControl
+	GetPeopleListFuncControl  mockeable.CallsFuncControl
+	GetFilmFuncControl        mockeable.CallsFuncControl
+	GetFilmsFuncControl       mockeable.CallsFuncControl
+	GetPlanetFuncControl      mockeable.CallsFuncControl
+	GetPlanetsFuncControl     mockeable.CallsFuncControl
+	GetSpeciesFuncControl     mockeable.CallsFuncControl
+	GetSpeciesListFuncControl mockeable.CallsFuncControl
+	GetVehicleFuncControl     mockeable.CallsFuncControl
+	GetVehiclesFuncControl    mockeable.CallsFuncControl
 }
 
-func (c *MockClient) GetStarship(id int) (models.Starship, error) {
+func (c *MockClient) GetStarship(ctx context.Context, id int) (models.Starship, error) {
 	c.GetStarshipFuncControl.IncreaseCallCount()
 
-	return c.GetStarshipFunc(id)
+	return c.GetStarshipFunc(ctx, id)
 }
 
-func (c *MockClient) GetStarships() (models.Starships, error) {
+func (c *MockClient) GetStarships(ctx context.Context, page int, search string) (models.Starships, error) {
 	c.GetStarshipsFuncControl.IncreaseCallCount()
 
-	return c.GetStarshipsFunc()
+	return c.GetStarshipsFunc(ctx, page, search)
 }
 
-func (c *MockClient) GetPeople(id int) (models.People, error) {
+func (c *MockClient) GetPeople(ctx context.Context, id int) (models.People, error) {
 	c.GetPeopleFuncControl.IncreaseCallCount()
 
-	return c.GetPeopleFunc(id)
+	return c.GetPeopleFunc(ctx, id)
 }
 
-func (c *MockClient) GetPeopleList() (models.PeopleList, error) {
+func (c *MockClient) GetPeopleList(ctx context.Context, page int, search string) (models.PeopleList, error) {
 	c.GetPeopleListFuncControl.IncreaseCallCount()
 
-	return c.GetPeopleListFunc()
+	return c.GetPeopleListFunc(ctx, page, search)
+}
+
+func (c *MockClient) GetFilm(ctx context.Context, id int) (models.Film, error) {
+	c.GetFilmFuncControl.IncreaseCallCount()
+
+	return c.GetFilmFunc(ctx, id)
+}
+
+func (c *MockClient) GetFilms(ctx context.Context, page int, search string) (models.Films, error) {
+	c.GetFilmsFuncControl.IncreaseCallCount()
+
+	return c.GetFilmsFunc(ctx, page, search)
+}
+
+func (c *MockClient) GetPlanet(ctx context.Context, id int) (models.Planet, error) {
+	c.GetPlanetFuncControl.IncreaseCallCount()
+
+	return c.GetPlanetFunc(ctx, id)
+}
+
+func (c *MockClient) GetPlanets(ctx context.Context, page int, search string) (models.Planets, error) {
+	c.GetPlanetsFuncControl.IncreaseCallCount()
+
+	return c.GetPlanetsFunc(ctx, page, search)
+}
+
+func (c *MockClient) GetSpecies(ctx context.Context, id int) (models.Species, error) {
+	c.GetSpeciesFuncControl.IncreaseCallCount()
+
+	return c.GetSpeciesFunc(ctx, id)
+}
+
+func (c *MockClient) GetSpeciesList(ctx context.Context, page int, search string) (models.SpeciesList, error) {
+	c.GetSpeciesListFuncControl.IncreaseCallCount()
+
+	return c.GetSpeciesListFunc(ctx, page, search)
+}
+
+func (c *MockClient) GetVehicle(ctx context.Context, id int) (models.Vehicle, error) {
+	c.GetVehicleFuncControl.IncreaseCallCount()
+
+	return c.GetVehicleFunc(ctx, id)
+}
+
+func (c *MockClient) GetVehicles(ctx context.Context, page int, search string) (models.Vehicles, error) {
+	c.GetVehiclesFuncControl.IncreaseCallCount()
+
+	return c.GetVehiclesFunc(ctx, page, search)
 }
 
 func (c *MockClient) Use() {
@@ -46,6 +111,14 @@ func (c *MockClient) Use() {
 	c.GetStarshipsFuncControl.SetFuncName("GetStarships")
 	c.GetPeopleFuncControl.SetFuncName("GetPeople")
 	c.GetPeopleListFuncControl.SetFuncName("GetPeopleList")
+	c.GetFilmFuncControl.SetFuncName("GetFilm")
+	c.GetFilmsFuncControl.SetFuncName("GetFilms")
+	c.GetPlanetFuncControl.SetFuncName("GetPlanet")
+	c.GetPlanetsFuncControl.SetFuncName("GetPlanets")
+	c.GetSpeciesFuncControl.SetFuncName("GetSpecies")
+	c.GetSpeciesListFuncControl.SetFuncName("GetSpeciesList")
+	c.GetVehicleFuncControl.SetFuncName("GetVehicle")
+	c.GetVehiclesFuncControl.SetFuncName("GetVehicles")
 
 	Instance = c
 }
@@ -60,5 +133,13 @@ func (c *MockClient) GetFuncControls() []*mockeable.CallsFuncControl {
 		&c.GetStarshipsFuncControl,
 		&c.GetPeopleFuncControl,
 		&c.GetPeopleListFuncControl,
+		&c.GetFilmFuncControl,
+		&c.GetFilmsFuncControl,
+		&c.GetPlanetFuncControl,
+		&c.GetPlanetsFuncControl,
+		&c.GetSpeciesFuncControl,
+		&c.GetSpeciesListFuncControl,
+		&c.GetVehicleFuncControl,
+		&c.GetVehiclesFuncControl,
 	}
 }
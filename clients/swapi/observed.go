@@ -0,0 +1,142 @@
+package swapi
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/models"
+)
+
+// Call outcomes reported to a CallObserver.
+const (
+	OutcomeOK             = "ok"
+	OutcomeNotFound       = "not_found"
+	OutcomeInternal       = "internal"
+	OutcomeTransportError = "transport_error"
+)
+
+// Call describes a finished call to a Client method.
+type Call struct {
+	Method   string
+	Outcome  string
+	Duration time.Duration
+}
+
+// CallObserver is notified of every call made through an Observed client.
+type CallObserver func(Call)
+
+// Observed reports every call to the wrapped client to an observer, with
+// its method, outcome and duration.
+type Observed struct {
+	client   Client
+	observer CallObserver
+}
+
+func NewObserved(client Client, observer CallObserver) *Observed {
+	return &Observed{client: client, observer: observer}
+}
+
+// observe runs fn and reports it as a call to method.
+func observe[T any](o *Observed, method string, fn func() (T, error)) (T, error) {
+	start := time.Now()
+	result, err := fn()
+
+	o.observer(Call{Method: method, Outcome: outcome(err), Duration: time.Since(start)})
+
+	return result, err
+}
+
+// outcome classifies err. Network errors, timeouts and cancellations are
+// transport errors; anything else, such as an upstream response that could
+// not be decoded or was too large, is internal.
+func outcome(err error) string {
+	var e *errors.Error
+	var netErr net.Error
+
+	switch {
+	case err == nil:
+		return OutcomeOK
+	case errors.As(err, &e) && e.Type == errors.NotFound:
+		return OutcomeNotFound
+	case errors.As(err, &e) && (e.Type == errors.Timeout || e.Type == errors.Canceled):
+		return OutcomeTransportError
+	case errors.As(err, &netErr), errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return OutcomeTransportError
+	default:
+		return OutcomeInternal
+	}
+}
+
+func (o *Observed) GetStarship(ctx context.Context, id int) (models.Starship, error) {
+	return observe(o, "GetStarship", func() (models.Starship, error) {
+		return o.client.GetStarship(ctx, id)
+	})
+}
+
+func (o *Observed) GetStarships(ctx context.Context, page int, search string) (models.Starships, error) {
+	return observe(o, "GetStarships", func() (models.Starships, error) {
+		return o.client.GetStarships(ctx, page, search)
+	})
+}
+
+func (o *Observed) GetPeople(ctx context.Context, id int) (models.People, error) {
+	return observe(o, "GetPeople", func() (models.People, error) {
+		return o.client.GetPeople(ctx, id)
+	})
+}
+
+func (o *Observed) GetPeopleList(ctx context.Context, page int, search string) (models.PeopleList, error) {
+	return observe(o, "GetPeopleList", func() (models.PeopleList, error) {
+		return o.client.GetPeopleList(ctx, page, search)
+	})
+}
+
+func (o *Observed) GetFilm(ctx context.Context, id int) (models.Film, error) {
+	return observe(o, "GetFilm", func() (models.Film, error) {
+		return o.client.GetFilm(ctx, id)
+	})
+}
+
+func (o *Observed) GetFilms(ctx context.Context, page int, search string) (models.Films, error) {
+	return observe(o, "GetFilms", func() (models.Films, error) {
+		return o.client.GetFilms(ctx, page, search)
+	})
+}
+
+func (o *Observed) GetPlanet(ctx context.Context, id int) (models.Planet, error) {
+	return observe(o, "GetPlanet", func() (models.Planet, error) {
+		return o.client.GetPlanet(ctx, id)
+	})
+}
+
+func (o *Observed) GetPlanets(ctx context.Context, page int, search string) (models.Planets, error) {
+	return observe(o, "GetPlanets", func() (models.Planets, error) {
+		return o.client.GetPlanets(ctx, page, search)
+	})
+}
+
+func (o *Observed) GetSpecies(ctx context.Context, id int) (models.Species, error) {
+	return observe(o, "GetSpecies", func() (models.Species, error) {
+		return o.client.GetSpecies(ctx, id)
+	})
+}
+
+func (o *Observed) GetSpeciesList(ctx context.Context, page int, search string) (models.SpeciesList, error) {
+	return observe(o, "GetSpeciesList", func() (models.SpeciesList, error) {
+		return o.client.GetSpeciesList(ctx, page, search)
+	})
+}
+
+func (o *Observed) GetVehicle(ctx context.Context, id int) (models.Vehicle, error) {
+	return observe(o, "GetVehicle", func() (models.Vehicle, error) {
+		return o.client.GetVehicle(ctx, id)
+	})
+}
+
+func (o *Observed) GetVehicles(ctx context.Context, page int, search string) (models.Vehicles, error) {
+	return observe(o, "GetVehicles", func() (models.Vehicles, error) {
+		return o.client.GetVehicles(ctx, page, search)
+	})
+}
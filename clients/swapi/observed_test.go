@@ -0,0 +1,62 @@
+package swapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObservedReportsOutcomes(t *testing.T) {
+	results := []error{
+		nil,
+		errors.NewNotFound("starships", "1"),
+		errors.NewInternal(),
+		errors.NewTimeout(),
+		&net.OpError{Op: "dial", Net: "tcp", Err: fmt.Errorf("connection refused")},
+		context.Canceled,
+		json.Unmarshal([]byte("{"), &models.Starship{}),
+		fmt.Errorf("upstream response exceeds %d bytes", 10),
+	}
+
+	calls := 0
+	client := &MockClient{
+		GetStarshipFunc: func(ctx context.Context, id int) (models.Starship, error) {
+			err := results[calls]
+			calls++
+			return models.Starship{}, err
+		},
+	}
+
+	observed := []Call{}
+	o := NewObserved(client, func(call Call) {
+		observed = append(observed, call)
+	})
+
+	for range results {
+		o.GetStarship(context.Background(), 1)
+	}
+
+	outcomes := []string{}
+
+	for _, call := range observed {
+		assert.Equal(t, "GetStarship", call.Method)
+		outcomes = append(outcomes, call.Outcome)
+	}
+
+	assert.Equal(t, []string{
+		OutcomeOK,
+		OutcomeNotFound,
+		OutcomeInternal,
+		OutcomeTransportError,
+		OutcomeTransportError,
+		OutcomeTransportError,
+		OutcomeInternal,
+		OutcomeInternal,
+	}, outcomes)
+}
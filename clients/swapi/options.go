@@ -0,0 +1,54 @@
+package swapi
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	DefaultBaseURL         = "https://swapi.dev/api"
+	DefaultTimeout         = 10 * time.Second
+	DefaultUserAgent       = "go-meli-test-dojo"
+	DefaultMaxResponseSize = 10 << 20
+)
+
+// Option customizes the client built by NewSWAPIClient.
+type Option func(*swapiClient)
+
+// WithBaseURL points the client at another SWAPI compatible server, such as
+// a mirror or a local stub.
+func WithBaseURL(baseURL string) Option {
+	return func(sw *swapiClient) {
+		sw.baseURL = baseURL
+	}
+}
+
+// WithTimeout limits the time spent on each upstream request, including
+// reading the response body. Zero disables the timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(sw *swapiClient) {
+		sw.client.Timeout = timeout
+	}
+}
+
+// WithTransport replaces the round tripper used for upstream requests.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(sw *swapiClient) {
+		sw.client.Transport = transport
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent upstream.
+func WithUserAgent(userAgent string) Option {
+	return func(sw *swapiClient) {
+		sw.userAgent = userAgent
+	}
+}
+
+// WithMaxResponseSize bounds how many bytes of an upstream response body are
+// read before giving up.
+func WithMaxResponseSize(size int64) Option {
+	return func(sw *swapiClient) {
+		sw.maxResponseSize = size
+	}
+}
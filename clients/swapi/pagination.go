@@ -0,0 +1,196 @@
+package swapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+)
+
+// maxConcurrentPages bounds how many upstream pages are fetched at the same
+// time while traversing a full collection.
+const maxConcurrentPages = 4
+
+// maxPages bounds the pages fetched concurrently from the count reported by
+// the first page. Larger counts, likely from a broken mirror, are followed
+// one "next" link at a time instead.
+const maxPages = 100
+
+// page mirrors the envelope SWAPI wraps around every list response. Its
+// layout matches the list types of the models package so it can be
+// converted to them directly.
+type page[T any] struct {
+	Count    int     `json:"count"`
+	Next     *string `json:"next"`
+	Previous *string `json:"previous"`
+	Results  []T     `json:"results"`
+}
+
+// RawPage is a page of a resource list with every result kept as the JSON
+// the upstream API sent.
+type RawPage struct {
+	Count    int               `json:"count"`
+	Next     *string           `json:"next"`
+	Previous *string           `json:"previous"`
+	Results  []json.RawMessage `json:"results"`
+}
+
+// GetRawPage fetches page number of resource, such as "people", without
+// decoding its results into models, so that no upstream field is lost.
+func (sw *swapiClient) GetRawPage(ctx context.Context, resource string, number int) (result RawPage, err error) {
+	err = sw.get(ctx, listResource("/"+resource+"/", number, ""), errors.NewNotFound(resource, ""), &result)
+
+	return result, err
+}
+
+// getList fetches a single page of resource when number is positive, or
+// traverses every upstream page and returns the full collection otherwise.
+// A non-empty search is forwarded upstream on every page request.
+func getList[T any](ctx context.Context, sw *swapiClient, resource string, number int, search string, notFound error) (result page[T], err error) {
+	if number > 0 {
+		err = sw.get(ctx, listResource(resource, number, search), notFound, &result)
+		return result, err
+	}
+
+	first := page[T]{}
+	err = sw.get(ctx, listResource(resource, 0, search), notFound, &first)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Count = first.Count
+	result.Results = first.Results
+
+	if first.Next == nil {
+		return result, nil
+	}
+
+	if len(first.Results) == 0 {
+		return followNext(ctx, sw, resource, search, first, notFound)
+	}
+
+	pageSize := len(first.Results)
+	total := (first.Count + pageSize - 1) / pageSize
+
+	if total > maxPages {
+		return followNext(ctx, sw, resource, search, first, notFound)
+	}
+
+	pages := make([]page[T], total+1)
+
+	// Stop fetching the remaining pages as soon as one of them fails and
+	// report that first failure rather than the cancellations it causes.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+
+	sem := make(chan struct{}, maxConcurrentPages)
+
+	for n := 2; n <= total && ctx.Err() == nil; n++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(n int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := sw.get(ctx, listResource(resource, n, search), notFound, &pages[n]); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(n)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return page[T]{}, firstErr
+	}
+
+	if ctx.Err() != nil {
+		return page[T]{}, transportError(ctx, ctx.Err())
+	}
+
+	for n := 2; n <= total; n++ {
+		result.Results = append(result.Results, pages[n].Results...)
+	}
+
+	return result, nil
+}
+
+// followNext walks the upstream "next" links one page at a time. It is used
+// when the page size cannot be inferred from the first page or the count is
+// implausibly large.
+func followNext[T any](ctx context.Context, sw *swapiClient, resource string, search string, current page[T], notFound error) (result page[T], err error) {
+	result.Count = current.Count
+	result.Results = current.Results
+
+	for current.Next != nil {
+		number, err := nextPageNumber(*current.Next)
+
+		if err != nil {
+			return page[T]{}, err
+		}
+
+		current = page[T]{}
+		err = sw.get(ctx, listResource(resource, number, search), notFound, &current)
+
+		if err != nil {
+			return page[T]{}, err
+		}
+
+		result.Results = append(result.Results, current.Results...)
+	}
+
+	return result, nil
+}
+
+// listResource appends the page and search query parameters to resource,
+// omitting the ones that are not set.
+func listResource(resource string, number int, search string) string {
+	query := url.Values{}
+
+	if number > 0 {
+		query.Set("page", strconv.Itoa(number))
+	}
+
+	if search != "" {
+		query.Set("search", search)
+	}
+
+	if len(query) == 0 {
+		return resource
+	}
+
+	return resource + "?" + query.Encode()
+}
+
+// nextPageNumber reads the page number out of an upstream "next" link so the
+// request can be rebuilt against the configured base URL.
+func nextPageNumber(link string) (int, error) {
+	parsed, err := url.Parse(link)
+
+	if err != nil {
+		return 0, err
+	}
+
+	number, err := strconv.Atoi(parsed.Query().Get("page"))
+
+	if err != nil {
+		return 0, fmt.Errorf("invalid next link: %q", link)
+	}
+
+	return number, nil
+}
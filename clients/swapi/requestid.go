@@ -0,0 +1,21 @@
+package swapi
+
+import "context"
+
+// RequestIDHeader is the header carrying the id of the request that caused
+// an upstream call.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID stores id in ctx so that upstream calls made with it send
+// it in the RequestIDHeader.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request id stored in ctx, or "" when there is none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,197 @@
+package swapi
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy controls how failed upstream requests are retried. Delays grow
+// exponentially from BaseDelay up to MaxDelay with full jitter.
+type RetryPolicy struct {
+	MaxAttempts     int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	RetryableStatus []int
+}
+
+// DefaultRetryPolicy retries transport errors and transient upstream
+// statuses up to three times.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+	RetryableStatus: []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	},
+}
+
+// Attempt describes a single upstream request made by the client.
+type Attempt struct {
+	Resource string
+	Number   int
+	Status   int
+	Err      error
+	Duration time.Duration
+}
+
+// AttemptObserver is notified after every upstream attempt, including the
+// ones that are retried.
+type AttemptObserver func(Attempt)
+
+// WithRetryPolicy replaces DefaultRetryPolicy. A policy with MaxAttempts of
+// one or less disables retries.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(sw *swapiClient) {
+		sw.retry = policy
+	}
+}
+
+// WithAttemptObserver registers a callback for per-attempt metrics.
+func WithAttemptObserver(observer AttemptObserver) Option {
+	return func(sw *swapiClient) {
+		sw.observer = observer
+	}
+}
+
+// do sends a GET request for resource, retrying transport errors and
+// retryable statuses according to the client retry policy.
+func (sw *swapiClient) do(ctx context.Context, resource string) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		res, err := sw.send(ctx, resource)
+
+		if sw.observer != nil {
+			observed := Attempt{Resource: resource, Number: attempt, Err: err, Duration: time.Since(start)}
+
+			if res != nil {
+				observed.Status = res.StatusCode
+			}
+
+			sw.observer(observed)
+		}
+
+		if attempt >= sw.retry.MaxAttempts || !sw.retry.retryable(ctx, res, err) {
+			if err != nil {
+				return nil, transportError(ctx, err)
+			}
+
+			return res, nil
+		}
+
+		delay, ok := sw.retry.delay(attempt, res)
+
+		if !ok {
+			return res, nil
+		}
+
+		if res != nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, transportError(ctx, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+func (sw *swapiClient) send(ctx context.Context, resource string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sw.baseURL+resource, nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if sw.userAgent != "" {
+		req.Header.Set("User-Agent", sw.userAgent)
+	}
+
+	if id := RequestID(ctx); id != "" {
+		req.Header.Set(RequestIDHeader, id)
+	}
+
+	return sw.client.Do(req)
+}
+
+func (p RetryPolicy) retryable(ctx context.Context, res *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	if err != nil {
+		return true
+	}
+
+	for _, status := range p.RetryableStatus {
+		if res.StatusCode == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// delay returns how long to wait before the next attempt. A Retry-After
+// header takes precedence over the backoff; when it asks for more than
+// MaxDelay the request is not retried at all.
+func (p RetryPolicy) delay(attempt int, res *http.Response) (time.Duration, bool) {
+	if res != nil {
+		if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+				return 0, false
+			}
+
+			return retryAfter, true
+		}
+	}
+
+	backoff := p.BaseDelay << uint(attempt-1)
+
+	if backoff <= 0 || (p.MaxDelay > 0 && backoff > p.MaxDelay) {
+		backoff = p.MaxDelay
+	}
+
+	if backoff <= 0 {
+		return 0, true
+	}
+
+	return time.Duration(rand.Int63n(int64(backoff) + 1)), true
+}
+
+// parseRetryAfter accepts both forms of the Retry-After header: a number of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+
+		if delay < 0 {
+			delay = 0
+		}
+
+		return delay, true
+	}
+
+	return 0, false
+}
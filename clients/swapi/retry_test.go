@@ -0,0 +1,156 @@
+package swapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/models"
+	"github.com/klasrak/go-meli-test-dojo/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxAttempts:     3,
+	BaseDelay:       time.Millisecond,
+	MaxDelay:        10 * time.Millisecond,
+	RetryableStatus: DefaultRetryPolicy.RetryableStatus,
+}
+
+// newFlakyServer answers with status for the first failures requests and
+// with a starship afterwards.
+func newFlakyServer(failures int32, status int, header http.Header) (*httptest.Server, *int32) {
+	calls := new(int32)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			for key, values := range header {
+				rw.Header()[key] = values
+			}
+
+			rw.WriteHeader(status)
+			return
+		}
+
+		rw.Write(utils.ToJSON(models.Starship{Name: "Death Star"}))
+	}))
+
+	return server, calls
+}
+
+type attemptRecorder struct {
+	mu       sync.Mutex
+	attempts []Attempt
+}
+
+func (r *attemptRecorder) observe(attempt Attempt) {
+	r.mu.Lock()
+	r.attempts = append(r.attempts, attempt)
+	r.mu.Unlock()
+}
+
+func TestRetrySucceedsAfterFailures(t *testing.T) {
+	server, calls := newFlakyServer(2, http.StatusServiceUnavailable, nil)
+	defer server.Close()
+
+	recorder := &attemptRecorder{}
+	client := NewSWAPIClient(WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy), WithAttemptObserver(recorder.observe))
+
+	result, err := client.GetStarship(context.Background(), 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Death Star", result.Name)
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+
+	assert.Len(t, recorder.attempts, 3)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.attempts[0].Status)
+	assert.Equal(t, http.StatusServiceUnavailable, recorder.attempts[1].Status)
+	assert.Equal(t, http.StatusOK, recorder.attempts[2].Status)
+	assert.Equal(t, 3, recorder.attempts[2].Number)
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	server, calls := newFlakyServer(5, http.StatusBadGateway, nil)
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy))
+
+	_, err := client.GetStarship(context.Background(), 9)
+
+	assert.Equal(t, http.StatusInternalServerError, errors.Status(err))
+	assert.Equal(t, int32(3), atomic.LoadInt32(calls))
+}
+
+func TestRetrySkipsNonRetryableStatus(t *testing.T) {
+	server, calls := newFlakyServer(1, http.StatusNotFound, nil)
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy))
+
+	_, err := client.GetStarship(context.Background(), 9)
+
+	assert.Equal(t, http.StatusNotFound, errors.Status(err))
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	server, calls := newFlakyServer(1, http.StatusTooManyRequests, http.Header{"Retry-After": []string{"0"}})
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy))
+
+	_, err := client.GetStarship(context.Background(), 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestRetryAfterBeyondMaxDelay(t *testing.T) {
+	server, calls := newFlakyServer(1, http.StatusServiceUnavailable, http.Header{"Retry-After": []string{"120"}})
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy))
+
+	_, err := client.GetStarship(context.Background(), 9)
+
+	assert.Equal(t, http.StatusInternalServerError, errors.Status(err))
+	assert.Equal(t, int32(1), atomic.LoadInt32(calls))
+}
+
+func TestRetryTransportErrors(t *testing.T) {
+	calls := new(int32)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) == 1 {
+			conn, _, _ := rw.(http.Hijacker).Hijack()
+			conn.Close()
+			return
+		}
+
+		rw.Write(utils.ToJSON(models.Starship{Name: "Death Star"}))
+	}))
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL), WithRetryPolicy(testRetryPolicy))
+
+	result, err := client.GetStarship(context.Background(), 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Death Star", result.Name)
+	assert.Equal(t, int32(2), atomic.LoadInt32(calls))
+}
+
+func TestRetryDelayIsBounded(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt := 1; attempt <= 10; attempt++ {
+		delay, ok := policy.delay(attempt, nil)
+
+		assert.True(t, ok)
+		assert.True(t, delay >= 0 && delay <= time.Second, "delay %s out of bounds", delay)
+	}
+}
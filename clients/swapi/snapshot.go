@@ -0,0 +1,243 @@
+package swapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/models"
+)
+
+// snapshotPageSize matches the page size of the upstream API.
+const snapshotPageSize = 10
+
+// NewSnapshotClient serves resources from a directory of JSON snapshot files
+// instead of the upstream API. Each resource lives in its own directory,
+// one file per id with the upstream payload, e.g. "starships/9.json".
+func NewSnapshotClient(dir string) (*snapshotClient, error) {
+	info, err := os.Stat(dir)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("snapshot %s is not a directory", dir)
+	}
+
+	return &snapshotClient{dir: dir}, nil
+}
+
+type snapshotClient struct {
+	dir string
+}
+
+func (sc *snapshotClient) GetStarship(ctx context.Context, id int) (models.Starship, error) {
+	return readSnapshot[models.Starship](ctx, sc, "starships", id)
+}
+
+func (sc *snapshotClient) GetStarships(ctx context.Context, page int, search string) (models.Starships, error) {
+	list, err := readSnapshotList(ctx, sc, "starships", page, search, func(s models.Starship) []string {
+		return []string{s.Name, s.Model}
+	})
+
+	return models.Starships(list), err
+}
+
+func (sc *snapshotClient) GetPeople(ctx context.Context, id int) (models.People, error) {
+	return readSnapshot[models.People](ctx, sc, "people", id)
+}
+
+func (sc *snapshotClient) GetPeopleList(ctx context.Context, page int, search string) (models.PeopleList, error) {
+	list, err := readSnapshotList(ctx, sc, "people", page, search, func(p models.People) []string {
+		return []string{p.Name}
+	})
+
+	return models.PeopleList(list), err
+}
+
+func (sc *snapshotClient) GetFilm(ctx context.Context, id int) (models.Film, error) {
+	return readSnapshot[models.Film](ctx, sc, "films", id)
+}
+
+func (sc *snapshotClient) GetFilms(ctx context.Context, page int, search string) (models.Films, error) {
+	list, err := readSnapshotList(ctx, sc, "films", page, search, func(f models.Film) []string {
+		return []string{f.Title}
+	})
+
+	return models.Films(list), err
+}
+
+func (sc *snapshotClient) GetPlanet(ctx context.Context, id int) (models.Planet, error) {
+	return readSnapshot[models.Planet](ctx, sc, "planets", id)
+}
+
+func (sc *snapshotClient) GetPlanets(ctx context.Context, page int, search string) (models.Planets, error) {
+	list, err := readSnapshotList(ctx, sc, "planets", page, search, func(p models.Planet) []string {
+		return []string{p.Name}
+	})
+
+	return models.Planets(list), err
+}
+
+func (sc *snapshotClient) GetSpecies(ctx context.Context, id int) (models.Species, error) {
+	return readSnapshot[models.Species](ctx, sc, "species", id)
+}
+
+func (sc *snapshotClient) GetSpeciesList(ctx context.Context, page int, search string) (models.SpeciesList, error) {
+	list, err := readSnapshotList(ctx, sc, "species", page, search, func(s models.Species) []string {
+		return []string{s.Name}
+	})
+
+	return models.SpeciesList(list), err
+}
+
+func (sc *snapshotClient) GetVehicle(ctx context.Context, id int) (models.Vehicle, error) {
+	return readSnapshot[models.Vehicle](ctx, sc, "vehicles", id)
+}
+
+func (sc *snapshotClient) GetVehicles(ctx context.Context, page int, search string) (models.Vehicles, error) {
+	list, err := readSnapshotList(ctx, sc, "vehicles", page, search, func(v models.Vehicle) []string {
+		return []string{v.Name, v.Model}
+	})
+
+	return models.Vehicles(list), err
+}
+
+// readSnapshot decodes the snapshot file of resource with the given id.
+func readSnapshot[T any](ctx context.Context, sc *snapshotClient, resource string, id int) (result T, err error) {
+	if err := ctx.Err(); err != nil {
+		return result, transportError(ctx, err)
+	}
+
+	body, err := ioutil.ReadFile(filepath.Join(sc.dir, resource, fmt.Sprintf("%d.json", id)))
+
+	if os.IsNotExist(err) {
+		return result, errors.NewNotFound(resource, fmt.Sprintf("%d", id))
+	}
+
+	if err != nil {
+		return result, err
+	}
+
+	if err = json.Unmarshal(body, &result); err != nil {
+		return result, err
+	}
+
+	identify(&result)
+
+	return result, nil
+}
+
+// readSnapshotList lists every snapshot of resource ordered by id, keeps the
+// ones with a field matching search and paginates them like the upstream
+// API does. A non-positive page returns the full collection.
+func readSnapshotList[T any](ctx context.Context, sc *snapshotClient, resource string, number int, search string, fields func(T) []string) (result page[T], err error) {
+	ids, err := sc.ids(resource)
+
+	if err != nil {
+		return result, err
+	}
+
+	result.Results = []T{}
+
+	for _, id := range ids {
+		item, err := readSnapshot[T](ctx, sc, resource, id)
+
+		if err != nil {
+			return page[T]{}, err
+		}
+
+		if matchesSearch(fields(item), search) {
+			result.Results = append(result.Results, item)
+		}
+	}
+
+	result.Count = len(result.Results)
+
+	if number <= 0 {
+		return result, nil
+	}
+
+	start := (number - 1) * snapshotPageSize
+
+	if start >= result.Count && !(number == 1 && result.Count == 0) {
+		return page[T]{}, errors.NewNotFound(resource, "")
+	}
+
+	end := start + snapshotPageSize
+
+	if end > result.Count {
+		end = result.Count
+	}
+
+	if end < result.Count {
+		next := DefaultBaseURL + listResource("/"+resource+"/", number+1, search)
+		result.Next = &next
+	}
+
+	if number > 1 {
+		previous := DefaultBaseURL + listResource("/"+resource+"/", number-1, search)
+		result.Previous = &previous
+	}
+
+	result.Results = result.Results[start:end]
+
+	return result, nil
+}
+
+// ids returns the ids of the snapshot files of resource in ascending order.
+func (sc *snapshotClient) ids(resource string) ([]int, error) {
+	entries, err := ioutil.ReadDir(filepath.Join(sc.dir, resource))
+
+	if os.IsNotExist(err) {
+		return nil, errors.NewNotFound(resource, "")
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int{}
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || filepath.Ext(name) != ".json" {
+			continue
+		}
+
+		if id, err := strconv.Atoi(strings.TrimSuffix(name, ".json")); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// matchesSearch mimics the upstream search: a case insensitive substring
+// match against any of the searchable fields.
+func matchesSearch(fields []string, search string) bool {
+	if search == "" {
+		return true
+	}
+
+	search = strings.ToLower(search)
+
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+
+	return false
+}
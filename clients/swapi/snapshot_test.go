@@ -0,0 +1,93 @@
+package swapi
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/models"
+	"github.com/klasrak/go-meli-test-dojo/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeSnapshot(t *testing.T, dir string, resource string, id int, v interface{}) {
+	resourceDir := filepath.Join(dir, resource)
+
+	if err := os.MkdirAll(resourceDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(resourceDir, fmt.Sprintf("%d.json", id)), utils.ToJSON(v), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSnapshotClientGetStarship(t *testing.T) {
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "starships", 9, models.Starship{Name: "Death Star", Model: "DS-1 Orbital Battle Station"})
+
+	client, err := NewSnapshotClient(dir)
+	assert.NoError(t, err)
+
+	result, err := client.GetStarship(context.Background(), 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Death Star", result.Name)
+}
+
+func TestSnapshotClientNotFound(t *testing.T) {
+	dir := t.TempDir()
+	writeSnapshot(t, dir, "people", 1, models.People{Name: "Luke Skywalker"})
+
+	client, err := NewSnapshotClient(dir)
+	assert.NoError(t, err)
+
+	_, err = client.GetPeople(context.Background(), 2)
+	assert.Equal(t, http.StatusNotFound, errors.Status(err))
+	assert.Equal(t, "resource: people with id: 2 not found", err.Error())
+
+	_, err = client.GetStarships(context.Background(), 0, "")
+	assert.Equal(t, http.StatusNotFound, errors.Status(err))
+}
+
+func TestSnapshotClientList(t *testing.T) {
+	dir := t.TempDir()
+
+	for id := 1; id <= 12; id++ {
+		writeSnapshot(t, dir, "people", id, models.People{Name: fmt.Sprintf("person %d", id)})
+	}
+
+	client, err := NewSnapshotClient(dir)
+	assert.NoError(t, err)
+
+	all, err := client.GetPeopleList(context.Background(), 0, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 12, all.Count)
+	assert.Equal(t, "person 1", all.Results[0].Name)
+	assert.Equal(t, "person 12", all.Results[11].Name)
+
+	second, err := client.GetPeopleList(context.Background(), 2, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 12, second.Count)
+	assert.Len(t, second.Results, 2)
+	assert.Nil(t, second.Next)
+	assert.NotNil(t, second.Previous)
+
+	_, err = client.GetPeopleList(context.Background(), 3, "")
+	assert.Equal(t, http.StatusNotFound, errors.Status(err))
+
+	found, err := client.GetPeopleList(context.Background(), 0, "SON 1")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, found.Count)
+}
+
+func TestNewSnapshotClientMissingDir(t *testing.T) {
+	_, err := NewSnapshotClient(filepath.Join(t.TempDir(), "missing"))
+
+	assert.Error(t, err)
+}
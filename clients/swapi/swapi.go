@@ -1,135 +1,193 @@
 package swapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 
 	"github.com/klasrak/go-meli-test-dojo/errors"
 	"github.com/klasrak/go-meli-test-dojo/models"
 )
 
-func NewSWAPIClient() *swapiClient {
-	return &swapiClient{
-		client:  &http.Client{},
-		baseURL: "https://swapi.dev/api",
+func NewSWAPIClient(opts ...Option) *swapiClient {
+	sw := &swapiClient{
+		client:          &http.Client{Timeout: DefaultTimeout},
+		baseURL:         DefaultBaseURL,
+		userAgent:       DefaultUserAgent,
+		maxResponseSize: DefaultMaxResponseSize,
+		retry:           DefaultRetryPolicy,
 	}
+
+	for _, opt := range opts {
+		opt(sw)
+	}
+
+	return sw
 }
 
 type swapiClient struct {
-	client  *http.Client
-	baseURL string
+	client          *http.Client
+	baseURL         string
+	userAgent       string
+	maxResponseSize int64
+	retry           RetryPolicy
+	observer        AttemptObserver
 }
 
-func (sw *swapiClient) GetStarship(id int) (result models.Starship, err error) {
+func (sw *swapiClient) GetStarship(ctx context.Context, id int) (result models.Starship, err error) {
 	resource := fmt.Sprintf("/starships/%d/", id)
-	res, err := sw.client.Get(sw.baseURL + resource)
+	err = sw.get(ctx, resource, errors.NewNotFound("starships", fmt.Sprintf("%d", id)), &result)
 
-	if err != nil {
-		return result, err
-	}
+	return result, err
+}
 
-	if res.StatusCode != http.StatusOK {
-		if res.StatusCode == http.StatusNotFound {
-			return result, errors.NewNotFound("starships", fmt.Sprintf("%d", id))
-		} else {
-			return result, errors.NewInternal()
-		}
-	}
+func (sw *swapiClient) GetStarships(ctx context.Context, page int, search string) (result models.Starships, err error) {
+	resource := "/starships/"
+	list, err := getList[models.Starship](ctx, sw, resource, page, search, errors.NewNotFound("starships", ""))
+	identify(list.Results)
 
-	err = getBody(res, &result)
+	return models.Starships(list), err
+}
 
-	if err != nil {
-		return result, err
-	}
+func (sw *swapiClient) GetPeople(ctx context.Context, id int) (result models.People, err error) {
+	resource := fmt.Sprintf("/people/%d/", id)
+	err = sw.get(ctx, resource, errors.NewNotFound("people", fmt.Sprintf("%d", id)), &result)
 
 	return result, err
 }
 
-func (sw *swapiClient) GetStarships() (result models.Starships, err error) {
-	resource := "/starships/"
-	res, err := sw.client.Get(sw.baseURL + resource)
+func (sw *swapiClient) GetPeopleList(ctx context.Context, page int, search string) (result models.PeopleList, err error) {
+	resource := "/people/"
+	list, err := getList[models.People](ctx, sw, resource, page, search, errors.NewNotFound("people", ""))
+	identify(list.Results)
 
-	if err != nil {
-		return result, err
-	}
+	return models.PeopleList(list), err
+}
 
-	if res.StatusCode != http.StatusOK {
-		if res.StatusCode == http.StatusNotFound {
-			return result, errors.NewNotFound("starships", "")
-		} else {
-			return result, errors.NewInternal()
-		}
-	}
+func (sw *swapiClient) GetFilm(ctx context.Context, id int) (result models.Film, err error) {
+	resource := fmt.Sprintf("/films/%d/", id)
+	err = sw.get(ctx, resource, errors.NewNotFound("films", fmt.Sprintf("%d", id)), &result)
 
-	err = getBody(res, &result)
+	return result, err
+}
 
-	if err != nil {
-		return result, err
-	}
+func (sw *swapiClient) GetFilms(ctx context.Context, page int, search string) (result models.Films, err error) {
+	resource := "/films/"
+	list, err := getList[models.Film](ctx, sw, resource, page, search, errors.NewNotFound("films", ""))
+
+	return models.Films(list), err
+}
+
+func (sw *swapiClient) GetPlanet(ctx context.Context, id int) (result models.Planet, err error) {
+	resource := fmt.Sprintf("/planets/%d/", id)
+	err = sw.get(ctx, resource, errors.NewNotFound("planets", fmt.Sprintf("%d", id)), &result)
 
 	return result, err
 }
 
-func (sw *swapiClient) GetPeople(id int) (result models.People, err error) {
-	resource := fmt.Sprintf("/people/%d/", id)
-	res, err := sw.client.Get(sw.baseURL + resource)
+func (sw *swapiClient) GetPlanets(ctx context.Context, page int, search string) (result models.Planets, err error) {
+	resource := "/planets/"
+	list, err := getList[models.Planet](ctx, sw, resource, page, search, errors.NewNotFound("planets", ""))
 
-	if err != nil {
-		return result, err
-	}
+	return models.Planets(list), err
+}
 
-	if res.StatusCode != http.StatusOK {
-		if res.StatusCode == http.StatusNotFound {
-			return result, errors.NewNotFound("people", fmt.Sprintf("%d", id))
-		} else {
-			return result, errors.NewInternal()
-		}
-	}
+func (sw *swapiClient) GetSpecies(ctx context.Context, id int) (result models.Species, err error) {
+	resource := fmt.Sprintf("/species/%d/", id)
+	err = sw.get(ctx, resource, errors.NewNotFound("species", fmt.Sprintf("%d", id)), &result)
+
+	return result, err
+}
 
-	err = getBody(res, &result)
+func (sw *swapiClient) GetSpeciesList(ctx context.Context, page int, search string) (result models.SpeciesList, err error) {
+	resource := "/species/"
+	list, err := getList[models.Species](ctx, sw, resource, page, search, errors.NewNotFound("species", ""))
 
-	if err != nil {
-		return result, err
-	}
+	return models.SpeciesList(list), err
+}
+
+func (sw *swapiClient) GetVehicle(ctx context.Context, id int) (result models.Vehicle, err error) {
+	resource := fmt.Sprintf("/vehicles/%d/", id)
+	err = sw.get(ctx, resource, errors.NewNotFound("vehicles", fmt.Sprintf("%d", id)), &result)
 
 	return result, err
 }
 
-func (sw *swapiClient) GetPeopleList() (result models.PeopleList, err error) {
-	resource := "/people/"
-	res, err := sw.client.Get(sw.baseURL + resource)
+func (sw *swapiClient) GetVehicles(ctx context.Context, page int, search string) (result models.Vehicles, err error) {
+	resource := "/vehicles/"
+	list, err := getList[models.Vehicle](ctx, sw, resource, page, search, errors.NewNotFound("vehicles", ""))
+
+	return models.Vehicles(list), err
+}
+
+// get fetches resource from the upstream API and decodes it into v,
+// returning notFound when the upstream answers with a 404.
+func (sw *swapiClient) get(ctx context.Context, resource string, notFound error, v interface{}) error {
+	res, err := sw.do(ctx, resource)
 
 	if err != nil {
-		return result, err
+		return err
 	}
 
 	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+
 		if res.StatusCode == http.StatusNotFound {
-			return result, errors.NewNotFound("people", "")
+			return notFound
 		} else {
-			return result, errors.NewInternal()
+			return errors.NewInternal()
 		}
 	}
 
-	err = getBody(res, &result)
-
-	if err != nil {
-		return result, err
+	if err := getBody(res, sw.maxResponseSize, v); err != nil {
+		return err
 	}
 
-	return result, err
+	identify(v)
+
+	return nil
+}
+
+// transportError translates deadline and cancellation failures into their
+// dedicated error types. Any other error is returned unchanged.
+func transportError(ctx context.Context, err error) error {
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return errors.NewTimeout()
+	case errors.Is(err, context.Canceled), ctx.Err() == context.Canceled:
+		return errors.NewCanceled()
+	default:
+		return err
+	}
 }
 
-func getBody(res *http.Response, v interface{}) error {
+// getBody decodes the response body into v. Bodies larger than maxSize are
+// rejected; a non-positive maxSize reads the body without limit.
+func getBody(res *http.Response, maxSize int64, v interface{}) error {
 	defer res.Body.Close()
-	body, err := ioutil.ReadAll(res.Body)
+
+	var reader io.Reader = res.Body
+
+	if maxSize > 0 {
+		reader = io.LimitReader(res.Body, maxSize+1)
+	}
+
+	body, err := ioutil.ReadAll(reader)
 
 	if err != nil {
 		return err
 	}
 
+	if maxSize > 0 && int64(len(body)) > maxSize {
+		return fmt.Errorf("upstream response exceeds %d bytes", maxSize)
+	}
+
 	err = json.Unmarshal(body, v)
 
 	if err != nil {
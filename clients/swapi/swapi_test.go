@@ -0,0 +1,258 @@
+package swapi
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/errors"
+	"github.com/klasrak/go-meli-test-dojo/models"
+	"github.com/klasrak/go-meli-test-dojo/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPagedServer(t *testing.T, count int, pageSize int) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		number := 1
+
+		if value := r.URL.Query().Get("page"); value != "" {
+			number, _ = strconv.Atoi(value)
+		}
+
+		list := models.Starships{Count: count}
+
+		for i := (number-1)*pageSize + 1; i <= number*pageSize && i <= count; i++ {
+			list.Results = append(list.Results, models.Starship{
+				Name: fmt.Sprintf("starship %d", i),
+				URL:  fmt.Sprintf("http://%s/starships/%d/", r.Host, i),
+			})
+		}
+
+		if len(list.Results) == 0 {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		if number*pageSize < count {
+			next := fmt.Sprintf("http://%s/starships/?page=%d", r.Host, number+1)
+			list.Next = &next
+		}
+
+		rw.Write(utils.ToJSON(list))
+	}))
+}
+
+func TestGetStarshipsTraversesAllPages(t *testing.T) {
+	server := newPagedServer(t, 36, 10)
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL))
+
+	result, err := client.GetStarships(context.Background(), 0, "")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 36, result.Count)
+	assert.Len(t, result.Results, 36)
+	assert.Nil(t, result.Next)
+
+	for i, starship := range result.Results {
+		assert.Equal(t, fmt.Sprintf("starship %d", i+1), starship.Name)
+		assert.Equal(t, i+1, starship.ID)
+	}
+}
+
+func TestGetStarshipsIgnoresImplausibleCount(t *testing.T) {
+	requests := int32(0)
+
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+
+		list := models.Starships{Count: 1000000000, Results: []models.Starship{{Name: "starship " + r.URL.Query().Get("page")}}}
+
+		if r.URL.Query().Get("page") == "" {
+			next := fmt.Sprintf("http://%s/starships/?page=2", r.Host)
+			list.Next = &next
+		}
+
+		rw.Write(utils.ToJSON(list))
+	}))
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL))
+
+	result, err := client.GetStarships(context.Background(), 0, "")
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Results, 2)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestGetStarshipsSinglePage(t *testing.T) {
+	server := newPagedServer(t, 36, 10)
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL))
+
+	result, err := client.GetStarships(context.Background(), 2, "")
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Results, 10)
+	assert.Equal(t, "starship 11", result.Results[0].Name)
+	assert.NotNil(t, result.Next)
+}
+
+func TestGetStarshipsPageNotFound(t *testing.T) {
+	server := newPagedServer(t, 36, 10)
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL))
+
+	_, err := client.GetStarships(context.Background(), 9, "")
+
+	assert.Equal(t, http.StatusNotFound, errors.Status(err))
+}
+
+func TestGetPeopleListForwardsSearch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/people/", r.URL.Path)
+		assert.Equal(t, "luke sky", r.URL.Query().Get("search"))
+
+		rw.Write(utils.ToJSON(models.PeopleList{Count: 1, Results: []models.People{{Name: "Luke Skywalker"}}}))
+	}))
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL))
+
+	result, err := client.GetPeopleList(context.Background(), 0, "luke sky")
+
+	assert.NoError(t, err)
+	assert.Len(t, result.Results, 1)
+}
+
+func TestGetPeopleMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(`{"name":"Luke Skywalker","created":"2014-12-09T13:50:51.644000Z","edited":"2014-12-20T21:17:56.891000Z","url":"https://swapi.dev/api/people/1/"}`))
+	}))
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL))
+
+	result, err := client.GetPeople(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.ID)
+	assert.Equal(t, "https://swapi.dev/api/people/1/", result.URL)
+	assert.Equal(t, time.Date(2014, 12, 9, 13, 50, 51, 644000000, time.UTC), result.Created)
+	assert.Equal(t, time.Date(2014, 12, 20, 21, 17, 56, 891000000, time.UTC), result.Edited)
+}
+
+func TestGetStarshipDeadlineExceeded(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewSWAPIClient(WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetStarship(ctx, 9)
+
+	assert.Equal(t, http.StatusGatewayTimeout, errors.Status(err))
+}
+
+func TestGetStarshipCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := NewSWAPIClient(WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	_, err := client.GetStarship(ctx, 9)
+
+	assert.Equal(t, errors.StatusClientClosedRequest, errors.Status(err))
+}
+
+func TestNewSWAPIClientSendsUserAgent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "dojo-tests", r.Header.Get("User-Agent"))
+
+		rw.Write(utils.ToJSON(models.Starship{Name: "Death Star"}))
+	}))
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL), WithUserAgent("dojo-tests"))
+
+	result, err := client.GetStarship(context.Background(), 9)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Death Star", result.Name)
+}
+
+func TestSWAPIClientForwardsRequestID(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "req-42", r.Header.Get(RequestIDHeader))
+
+		rw.Write(utils.ToJSON(models.Starship{Name: "Death Star"}))
+	}))
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL))
+
+	_, err := client.GetStarship(WithRequestID(context.Background(), "req-42"), 9)
+
+	assert.NoError(t, err)
+}
+
+func TestNewSWAPIClientMaxResponseSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write(utils.ToJSON(models.Starship{Name: strings.Repeat("x", 64)}))
+	}))
+	defer server.Close()
+
+	client := NewSWAPIClient(WithBaseURL(server.URL), WithMaxResponseSize(32))
+
+	_, err := client.GetStarship(context.Background(), 9)
+
+	assert.Error(t, err)
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestNewSWAPIClientTransport(t *testing.T) {
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "http://mirror.local/api/people/1/", r.URL.String())
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(`{"name":"Luke Skywalker"}`)),
+		}, nil
+	})
+
+	client := NewSWAPIClient(WithBaseURL("http://mirror.local/api"), WithTransport(transport))
+
+	result, err := client.GetPeople(context.Background(), 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Luke Skywalker", result.Name)
+}
@@ -0,0 +1,54 @@
+package swapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/klasrak/go-meli-test-dojo/models"
+)
+
+// ParseID extracts the numeric resource id from a SWAPI resource URL such
+// as "https://swapi.dev/api/planets/1/".
+func ParseID(url string) (int, error) {
+	_, id, err := ParseResource(url)
+
+	return id, err
+}
+
+// ParseResource extracts the resource name and id from a SWAPI resource URL,
+// e.g. "planets" and 1 from "https://swapi.dev/api/planets/1/".
+func ParseResource(url string) (string, int, error) {
+	parts := strings.Split(strings.TrimRight(url, "/"), "/")
+
+	if len(parts) < 2 {
+		return "", 0, fmt.Errorf("invalid resource url: %q", url)
+	}
+
+	id, err := strconv.Atoi(parts[len(parts)-1])
+
+	if err != nil || id <= 0 || parts[len(parts)-2] == "" {
+		return "", 0, fmt.Errorf("invalid resource url: %q", url)
+	}
+
+	return parts[len(parts)-2], id, nil
+}
+
+// identify fills the numeric id of the models that expose one, either a
+// pointer to a single item or a slice of items, from their resource URL.
+func identify(v interface{}) {
+	switch item := v.(type) {
+	case *models.Starship:
+		item.ID, _ = ParseID(item.URL)
+	case *models.People:
+		item.ID, _ = ParseID(item.URL)
+	case []models.Starship:
+		for i := range item {
+			identify(&item[i])
+		}
+	case []models.People:
+		for i := range item {
+			identify(&item[i])
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package config
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting of the server and of the SWAPI client.
+type Config struct {
+	Server Server `yaml:"server"`
+	SWAPI  SWAPI  `yaml:"swapi"`
+}
+
+// Server configures the HTTP server.
+type Server struct {
+	Addr            string        `yaml:"addr"`
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	ReadinessDrain  time.Duration `yaml:"readiness_drain"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+	TrustProxy      bool          `yaml:"trust_proxy"`
+	RateLimit       RateLimit     `yaml:"rate_limit"`
+}
+
+// RateLimit configures the token buckets that limit the requests of each
+// client. A zero Burst disables rate limiting. Only the APIKeys identify a
+// client by key; other clients are limited by IP address. At most MaxClients
+// buckets are kept.
+type RateLimit struct {
+	Burst      int      `yaml:"burst"`
+	Refill     float64  `yaml:"refill"`
+	APIKeys    []string `yaml:"api_keys"`
+	MaxClients int      `yaml:"max_clients"`
+}
+
+// SWAPI configures the client of the upstream API.
+type SWAPI struct {
+	BaseURL          string        `yaml:"base_url"`
+	Timeout          time.Duration `yaml:"timeout"`
+	UserAgent        string        `yaml:"user_agent"`
+	MaxResponseSize  int64         `yaml:"max_response_size"`
+	RetryAttempts    int           `yaml:"retry_attempts"`
+	BreakerThreshold int           `yaml:"breaker_threshold"`
+	BreakerCoolDown  time.Duration `yaml:"breaker_cooldown"`
+	CacheSize        int           `yaml:"cache_size"`
+	CacheTTL         time.Duration `yaml:"cache_ttl"`
+	SnapshotDir      string        `yaml:"snapshot_dir"`
+	SnapshotRoot     string        `yaml:"snapshot_root"`
+}
+
+// Default returns the server defaults. The SWAPI client settings are left
+// empty for the caller to fill with the client's own defaults before
+// passing them to Load.
+func Default() Config {
+	return Config{
+		Server: Server{
+			Addr:            ":3000",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    60 * time.Second,
+			IdleTimeout:     120 * time.Second,
+			ReadinessDrain:  5 * time.Second,
+			ShutdownTimeout: 15 * time.Second,
+			RateLimit: RateLimit{
+				Burst:      60,
+				Refill:     1,
+				MaxClients: 10000,
+			},
+		},
+		SWAPI: SWAPI{
+			SnapshotRoot: "tmp/snapshots",
+		},
+	}
+}
+
+// FileEnv is the environment variable holding the path of the YAML file,
+// which can also be given with the -config flag.
+const FileEnv = "CONFIG_FILE"
+
+// env maps each flag to the environment variable that sets it.
+var env = map[string]string{
+	"addr":                    "ADDR",
+	"read-timeout":            "READ_TIMEOUT",
+	"write-timeout":           "WRITE_TIMEOUT",
+	"idle-timeout":            "IDLE_TIMEOUT",
+	"readiness-drain":         "READINESS_DRAIN",
+	"shutdown-timeout":        "SHUTDOWN_TIMEOUT",
+	"trust-proxy":             "TRUST_PROXY",
+	"rate-limit-burst":        "RATE_LIMIT_BURST",
+	"rate-limit-refill":       "RATE_LIMIT_REFILL",
+	"rate-limit-api-keys":     "RATE_LIMIT_API_KEYS",
+	"rate-limit-max-clients":  "RATE_LIMIT_MAX_CLIENTS",
+	"swapi-url":               "SWAPI_BASE_URL",
+	"swapi-timeout":           "SWAPI_TIMEOUT",
+	"swapi-user-agent":        "SWAPI_USER_AGENT",
+	"swapi-max-response-size": "SWAPI_MAX_RESPONSE_SIZE",
+	"swapi-retry-attempts":    "SWAPI_RETRY_ATTEMPTS",
+	"swapi-breaker-threshold": "SWAPI_BREAKER_THRESHOLD",
+	"swapi-breaker-cooldown":  "SWAPI_BREAKER_COOLDOWN",
+	"swapi-cache-size":        "SWAPI_CACHE_SIZE",
+	"swapi-cache-ttl":         "SWAPI_CACHE_TTL",
+	"snapshot-dir":            "SWAPI_SNAPSHOT_DIR",
+	"snapshot-root":           "SWAPI_SNAPSHOT_ROOT",
+}
+
+// Load parses args with fs and builds the configuration from defaults, the
+// optional YAML file, the environment and the flags, each one taking
+// precedence over the previous ones. The result is validated.
+func Load(fs *flag.FlagSet, args []string, defaults Config) (Config, error) {
+	var flags Config
+
+	file := fs.String("config", os.Getenv(FileEnv), "path of a YAML configuration file")
+	register(fs, &flags, defaults)
+
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+
+	cfg := defaults
+
+	if *file != "" {
+		if err := loadFile(*file, &cfg); err != nil {
+			return Config{}, err
+		}
+	}
+
+	// Environment variables and flags are applied through a flag set bound
+	// to cfg, so both are parsed the same way.
+	bound := flag.NewFlagSet(fs.Name(), flag.ContinueOnError)
+	register(bound, &cfg, cfg)
+
+	for name, key := range env {
+		if value, ok := os.LookupEnv(key); ok {
+			if err := bound.Set(name, value); err != nil {
+				return Config{}, fmt.Errorf("invalid %s: %v", key, err)
+			}
+		}
+	}
+
+	var err error
+
+	fs.Visit(func(f *flag.Flag) {
+		if bound.Lookup(f.Name) != nil && err == nil {
+			err = bound.Set(f.Name, f.Value.String())
+		}
+	})
+
+	if err != nil {
+		return Config{}, err
+	}
+
+	return cfg, cfg.Validate()
+}
+
+func loadFile(path string, cfg *Config) error {
+	content, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil && err != io.EOF {
+		return fmt.Errorf("invalid config file %s: %v", path, err)
+	}
+
+	return nil
+}
+
+// register defines on fs one flag per setting, bound to cfg and starting
+// with the values of defaults.
+func register(fs *flag.FlagSet, cfg *Config, defaults Config) {
+	fs.StringVar(&cfg.Server.Addr, "addr", defaults.Server.Addr, "address the server listens on")
+	fs.DurationVar(&cfg.Server.ReadTimeout, "read-timeout", defaults.Server.ReadTimeout, "maximum time to read a request, 0 disables it")
+	fs.DurationVar(&cfg.Server.WriteTimeout, "write-timeout", defaults.Server.WriteTimeout, "maximum time to write a response, 0 disables it")
+	fs.DurationVar(&cfg.Server.IdleTimeout, "idle-timeout", defaults.Server.IdleTimeout, "time idle keep-alive connections stay open, 0 disables it")
+	fs.DurationVar(&cfg.Server.ReadinessDrain, "readiness-drain", defaults.Server.ReadinessDrain, "time the server reports not ready before it stops accepting connections")
+	fs.DurationVar(&cfg.Server.ShutdownTimeout, "shutdown-timeout", defaults.Server.ShutdownTimeout, "time in-flight requests get to finish on shutdown")
+	fs.BoolVar(&cfg.Server.TrustProxy, "trust-proxy", defaults.Server.TrustProxy, "take the caller's scheme, host and address from the X-Forwarded-* headers of a proxy")
+	fs.IntVar(&cfg.Server.RateLimit.Burst, "rate-limit-burst", defaults.Server.RateLimit.Burst, "requests a client can make at once, 0 disables rate limiting")
+	fs.Float64Var(&cfg.Server.RateLimit.Refill, "rate-limit-refill", defaults.Server.RateLimit.Refill, "requests per second a client regains")
+	stringListVar(fs, &cfg.Server.RateLimit.APIKeys, "rate-limit-api-keys", defaults.Server.RateLimit.APIKeys, "comma separated API keys that get a bucket of their own")
+	fs.IntVar(&cfg.Server.RateLimit.MaxClients, "rate-limit-max-clients", defaults.Server.RateLimit.MaxClients, "maximum number of clients tracked at once")
+	fs.StringVar(&cfg.SWAPI.BaseURL, "swapi-url", defaults.SWAPI.BaseURL, "base URL of the SWAPI server")
+	fs.DurationVar(&cfg.SWAPI.Timeout, "swapi-timeout", defaults.SWAPI.Timeout, "timeout of each upstream request")
+	fs.StringVar(&cfg.SWAPI.UserAgent, "swapi-user-agent", defaults.SWAPI.UserAgent, "User-Agent sent upstream")
+	fs.Int64Var(&cfg.SWAPI.MaxResponseSize, "swapi-max-response-size", defaults.SWAPI.MaxResponseSize, "maximum upstream response size in bytes")
+	fs.IntVar(&cfg.SWAPI.RetryAttempts, "swapi-retry-attempts", defaults.SWAPI.RetryAttempts, "maximum attempts per upstream request")
+	fs.IntVar(&cfg.SWAPI.BreakerThreshold, "swapi-breaker-threshold", defaults.SWAPI.BreakerThreshold, "consecutive upstream failures that open the circuit breaker")
+	fs.DurationVar(&cfg.SWAPI.BreakerCoolDown, "swapi-breaker-cooldown", defaults.SWAPI.BreakerCoolDown, "time the circuit breaker stays open")
+	fs.IntVar(&cfg.SWAPI.CacheSize, "swapi-cache-size", defaults.SWAPI.CacheSize, "maximum number of cached upstream responses, 0 disables the cache")
+	fs.DurationVar(&cfg.SWAPI.CacheTTL, "swapi-cache-ttl", defaults.SWAPI.CacheTTL, "time upstream responses stay cached")
+	fs.StringVar(&cfg.SWAPI.SnapshotDir, "snapshot-dir", defaults.SWAPI.SnapshotDir, "serve resources from a snapshot directory instead of the upstream API")
+	fs.StringVar(&cfg.SWAPI.SnapshotRoot, "snapshot-root", defaults.SWAPI.SnapshotRoot, "directory where sync writes snapshot versions")
+}
+
+// stringList is a flag holding comma separated values.
+type stringList struct {
+	values *[]string
+}
+
+// stringListVar defines on fs a comma separated list flag stored in p, in
+// the manner of fs.StringVar.
+func stringListVar(fs *flag.FlagSet, p *[]string, name string, value []string, usage string) {
+	*p = value
+	fs.Var(&stringList{values: p}, name, usage)
+}
+
+func (l *stringList) String() string {
+	if l.values == nil {
+		return ""
+	}
+
+	return strings.Join(*l.values, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l.values = nil
+
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*l.values = append(*l.values, item)
+		}
+	}
+
+	return nil
+}
+
+// Validate reports every invalid setting of cfg in a single error.
+func (cfg Config) Validate() error {
+	problems := []string{}
+	check := func(ok bool, problem string) {
+		if !ok {
+			problems = append(problems, problem)
+		}
+	}
+
+	check(cfg.Server.Addr != "", "server addr is required")
+	check(cfg.Server.ReadTimeout >= 0, "server read_timeout must not be negative")
+	check(cfg.Server.WriteTimeout >= 0, "server write_timeout must not be negative")
+	check(cfg.Server.IdleTimeout >= 0, "server idle_timeout must not be negative")
+	check(cfg.Server.ReadinessDrain >= 0, "server readiness_drain must not be negative")
+	check(cfg.Server.ShutdownTimeout > 0, "server shutdown_timeout must be positive")
+	check(cfg.Server.RateLimit.Burst >= 0, "server rate_limit.burst must not be negative")
+	check(cfg.Server.RateLimit.Burst == 0 || cfg.Server.RateLimit.Refill > 0, "server rate_limit.refill must be positive")
+	check(cfg.Server.RateLimit.Burst == 0 || cfg.Server.RateLimit.MaxClients > 0, "server rate_limit.max_clients must be positive")
+
+	base, err := url.Parse(cfg.SWAPI.BaseURL)
+	check(err == nil && (base.Scheme == "http" || base.Scheme == "https") && base.Host != "", "swapi base_url must be an absolute http(s) URL")
+	check(cfg.SWAPI.Timeout > 0, "swapi timeout must be positive")
+	check(cfg.SWAPI.MaxResponseSize >= 0, "swapi max_response_size must not be negative")
+	check(cfg.SWAPI.RetryAttempts >= 1, "swapi retry_attempts must be at least 1")
+	check(cfg.SWAPI.BreakerThreshold >= 1, "swapi breaker_threshold must be at least 1")
+	check(cfg.SWAPI.BreakerCoolDown > 0, "swapi breaker_cooldown must be positive")
+	check(cfg.SWAPI.CacheSize >= 0, "swapi cache_size must not be negative")
+	check(cfg.SWAPI.CacheTTL > 0, "swapi cache_ttl must be positive")
+	check(cfg.SWAPI.SnapshotRoot != "", "swapi snapshot_root is required")
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid configuration: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
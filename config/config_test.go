@@ -0,0 +1,124 @@
+package config
+
+import (
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+// defaults are the server defaults completed with SWAPI client settings,
+// as main builds them.
+func defaults() Config {
+	cfg := Default()
+	cfg.SWAPI.BaseURL = "https://swapi.dev/api"
+	cfg.SWAPI.Timeout = 10 * time.Second
+	cfg.SWAPI.RetryAttempts = 3
+	cfg.SWAPI.BreakerThreshold = 5
+	cfg.SWAPI.BreakerCoolDown = 30 * time.Second
+	cfg.SWAPI.CacheSize = 1000
+	cfg.SWAPI.CacheTTL = 10 * time.Minute
+
+	return cfg
+}
+
+func load(args ...string) (Config, error) {
+	return Load(flag.NewFlagSet("test", flag.ContinueOnError), args, defaults())
+}
+
+func TestLoadDefaults(t *testing.T) {
+	cfg, err := load()
+
+	assert.NoError(t, err)
+	assert.Equal(t, defaults(), cfg)
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfigFile(t, `
+server:
+  addr: ":4000"
+  read_timeout: 5s
+  write_timeout: 30s
+swapi:
+  base_url: http://mirror.local/api
+  retry_attempts: 5
+`)
+
+	t.Setenv("WRITE_TIMEOUT", "20s")
+	t.Setenv("SWAPI_RETRY_ATTEMPTS", "2")
+
+	cfg, err := load("-config", path, "-swapi-retry-attempts", "4")
+
+	assert.NoError(t, err)
+	assert.Equal(t, ":4000", cfg.Server.Addr)
+	assert.Equal(t, 5*time.Second, cfg.Server.ReadTimeout)
+	assert.Equal(t, 20*time.Second, cfg.Server.WriteTimeout)
+	assert.Equal(t, defaults().Server.IdleTimeout, cfg.Server.IdleTimeout)
+	assert.Equal(t, "http://mirror.local/api", cfg.SWAPI.BaseURL)
+	assert.Equal(t, 4, cfg.SWAPI.RetryAttempts)
+}
+
+func TestLoadFileFromEnvironment(t *testing.T) {
+	t.Setenv(FileEnv, writeConfigFile(t, "server:\n  addr: \":5000\"\n"))
+
+	cfg, err := load()
+
+	assert.NoError(t, err)
+	assert.Equal(t, ":5000", cfg.Server.Addr)
+}
+
+func TestLoadEmptyFile(t *testing.T) {
+	cfg, err := load("-config", writeConfigFile(t, ""))
+
+	assert.NoError(t, err)
+	assert.Equal(t, defaults(), cfg)
+}
+
+func TestLoadUnknownFileField(t *testing.T) {
+	_, err := load("-config", writeConfigFile(t, "server:\n  port: 3000\n"))
+
+	assert.Error(t, err)
+}
+
+func TestLoadInvalidEnvironment(t *testing.T) {
+	t.Setenv("SWAPI_TIMEOUT", "soon")
+
+	_, err := load()
+
+	assert.EqualError(t, err, `invalid SWAPI_TIMEOUT: parse error`)
+}
+
+func TestLoadValidation(t *testing.T) {
+	_, err := load("-addr", "", "-swapi-url", "swapi.dev", "-swapi-retry-attempts", "0", "-read-timeout", "-1s")
+
+	assert.EqualError(t, err, "invalid configuration: server addr is required; server read_timeout must not be negative; swapi base_url must be an absolute http(s) URL; swapi retry_attempts must be at least 1")
+}
+
+func TestLoadAPIKeys(t *testing.T) {
+	path := writeConfigFile(t, "server:\n  rate_limit:\n    api_keys: [file-key]\n")
+
+	t.Setenv("RATE_LIMIT_API_KEYS", "env-key, other-key")
+
+	cfg, err := load("-config", path)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"env-key", "other-key"}, cfg.Server.RateLimit.APIKeys)
+
+	cfg, err = load("-config", path, "-rate-limit-api-keys", "flag-key")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"flag-key"}, cfg.Server.RateLimit.APIKeys)
+}
@@ -9,11 +9,19 @@ import (
 type Type string
 
 const (
-	BadRequest Type = "BAD_REQUEST"
-	Internal   Type = "INTERNAL_SERVER_ERROR"
-	NotFound   Type = "NOT_FOUND"
+	BadRequest      Type = "BAD_REQUEST"
+	Internal        Type = "INTERNAL_SERVER_ERROR"
+	NotFound        Type = "NOT_FOUND"
+	Timeout         Type = "GATEWAY_TIMEOUT"
+	Canceled        Type = "CLIENT_CLOSED_REQUEST"
+	Unavailable     Type = "SERVICE_UNAVAILABLE"
+	TooManyRequests Type = "TOO_MANY_REQUESTS"
 )
 
+// StatusClientClosedRequest is the non-standard status used when the caller
+// gives up on the request before a response is ready.
+const StatusClientClosedRequest = 499
+
 type Error struct {
 	Type    Type   `json:"type"`
 	Message string `json:"message"`
@@ -31,6 +39,14 @@ func (e *Error) Status() int {
 		return http.StatusInternalServerError
 	case NotFound:
 		return http.StatusNotFound
+	case Timeout:
+		return http.StatusGatewayTimeout
+	case Canceled:
+		return StatusClientClosedRequest
+	case Unavailable:
+		return http.StatusServiceUnavailable
+	case TooManyRequests:
+		return http.StatusTooManyRequests
 	default:
 		return http.StatusInternalServerError
 	}
@@ -80,3 +96,45 @@ func NewNotFound(name string, value string) *Error {
 		Message: message,
 	}
 }
+
+// NewTimeout for 504 errors
+func NewTimeout() *Error {
+	return &Error{
+		Type:    Timeout,
+		Message: "Upstream request timed out.",
+	}
+}
+
+// NewCanceled for requests canceled by the caller
+func NewCanceled() *Error {
+	return &Error{
+		Type:    Canceled,
+		Message: "Request canceled.",
+	}
+}
+
+// NewUnavailable for 503 errors, used when the upstream API is known to be down
+func NewUnavailable() *Error {
+	return &Error{
+		Type:    Unavailable,
+		Message: "Upstream unavailable.",
+	}
+}
+
+// NewTooManyRequests for 429 errors, used when a client exceeds its rate limit
+func NewTooManyRequests() *Error {
+	return &Error{
+		Type:    TooManyRequests,
+		Message: "Too many requests.",
+	}
+}
+
+// Is reports whether any error in err's chain matches target.
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As finds the first error in err's chain that matches target.
+func As(err error, target interface{}) bool {
+	return errors.As(err, target)
+}
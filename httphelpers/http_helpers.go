@@ -7,26 +7,78 @@ import (
 	"github.com/klasrak/go-meli-test-dojo/utils"
 )
 
+// Error writes err with the status code that matches its type. Errors
+// without a known type are reported as internal server errors.
+func Error(rw http.ResponseWriter, err error) {
+	switch errors.Status(err) {
+	case http.StatusBadRequest:
+		BadRequest(rw, err)
+	case http.StatusNotFound:
+		NotFound(rw, err)
+	case http.StatusGatewayTimeout:
+		GatewayTimeout(rw, err)
+	case errors.StatusClientClosedRequest:
+		ClientClosedRequest(rw, err)
+	case http.StatusServiceUnavailable:
+		ServiceUnavailable(rw, err)
+	case http.StatusTooManyRequests:
+		TooManyRequests(rw, err)
+	default:
+		InternalServerError(rw)
+	}
+}
+
 func BadRequest(rw http.ResponseWriter, err error) {
-	rw.WriteHeader(http.StatusBadRequest)
 	rw.Header().Add("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusBadRequest)
 	rw.Write(utils.ToJSON(err))
 }
 
 func InternalServerError(rw http.ResponseWriter) {
-	rw.WriteHeader(http.StatusInternalServerError)
 	rw.Header().Add("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusInternalServerError)
 	rw.Write(utils.ToJSON(errors.NewInternal()))
 }
 
 func NotFound(rw http.ResponseWriter, err error) {
+	rw.Header().Add("Content-Type", "application/json")
 	rw.WriteHeader(http.StatusNotFound)
+	rw.Write(utils.ToJSON(err))
+}
+
+func GatewayTimeout(rw http.ResponseWriter, err error) {
+	rw.Header().Add("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusGatewayTimeout)
+	rw.Write(utils.ToJSON(err))
+}
+
+func ClientClosedRequest(rw http.ResponseWriter, err error) {
 	rw.Header().Add("Content-Type", "application/json")
+	rw.WriteHeader(errors.StatusClientClosedRequest)
+	rw.Write(utils.ToJSON(err))
+}
+
+func ServiceUnavailable(rw http.ResponseWriter, err error) {
+	rw.Header().Add("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusServiceUnavailable)
+	rw.Write(utils.ToJSON(err))
+}
+
+func TooManyRequests(rw http.ResponseWriter, err error) {
+	rw.Header().Add("Content-Type", "application/json")
+	rw.WriteHeader(http.StatusTooManyRequests)
 	rw.Write(utils.ToJSON(err))
 }
 
 func OK(rw http.ResponseWriter, data interface{}) {
+	rw.Header().Add("Content-Type", "application/json")
 	rw.WriteHeader(http.StatusOK)
+	rw.Write(utils.ToJSON(data))
+}
+
+// JSON writes data with an arbitrary status code.
+func JSON(rw http.ResponseWriter, status int, data interface{}) {
 	rw.Header().Add("Content-Type", "application/json")
+	rw.WriteHeader(status)
 	rw.Write(utils.ToJSON(data))
 }
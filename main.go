@@ -1,11 +1,147 @@
 package main
 
-import "github.com/klasrak/go-meli-test-dojo/api"
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/klasrak/go-meli-test-dojo/api"
+	"github.com/klasrak/go-meli-test-dojo/clients/swapi"
+	"github.com/klasrak/go-meli-test-dojo/config"
+	"github.com/klasrak/go-meli-test-dojo/snapshots"
+)
 
 func main() {
-	api := api.New()
+	if len(os.Args) > 1 && os.Args[1] == "sync" {
+		runSync(os.Args[2:])
+		return
+	}
+
+	runServer(os.Args[1:])
+}
+
+func runServer(args []string) {
+	cfg, err := config.Load(flag.NewFlagSet("server", flag.ExitOnError), args, defaults())
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	client, err := newClient(cfg.SWAPI)
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	swapi.Instance = client
+	api.SetUpstreamBaseURL(cfg.SWAPI.BaseURL)
+
+	server := api.New(cfg.Server, os.Stdout)
+
+	log.Printf("listening on %s", server.Server.Addr)
+
+	if err := server.Run(); err != nil {
+		log.Fatalf("server stopped: %v", err)
+	}
+
+	log.Print("server stopped")
+}
+
+// runSync downloads every page of the upstream resources into a versioned
+// snapshot directory, resuming an interrupted run when there is one.
+func runSync(args []string) {
+	fs := flag.NewFlagSet("sync", flag.ExitOnError)
+	resources := fs.String("resources", "", "comma separated resources to crawl, all of them when empty")
+	cfg, err := config.Load(fs, args, defaults())
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var names []string
+
+	if *resources != "" {
+		names = strings.Split(*resources, ",")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	dir, manifest, err := snapshots.Sync(ctx, swapi.NewSWAPIClient(swapiOptions(cfg.SWAPI)...), cfg.SWAPI.SnapshotRoot, names)
+
+	if err != nil {
+		log.Fatalf("sync into %s stopped, run it again to resume: %v", dir, err)
+	}
+
+	for _, name := range snapshots.Resources() {
+		if resource, ok := manifest.Resources[name]; ok {
+			log.Printf("%s: %d files", name, resource.Count)
+		}
+	}
+
+	log.Printf("snapshot written to %s", dir)
+}
+
+// defaults completes the server defaults with the SWAPI client's own
+// defaults, keeping the config package independent of the client.
+func defaults() config.Config {
+	cfg := config.Default()
+	cfg.SWAPI.BaseURL = swapi.DefaultBaseURL
+	cfg.SWAPI.Timeout = swapi.DefaultTimeout
+	cfg.SWAPI.UserAgent = swapi.DefaultUserAgent
+	cfg.SWAPI.MaxResponseSize = swapi.DefaultMaxResponseSize
+	cfg.SWAPI.RetryAttempts = swapi.DefaultRetryPolicy.MaxAttempts
+	cfg.SWAPI.BreakerThreshold = swapi.DefaultBreakerConfig.FailureThreshold
+	cfg.SWAPI.BreakerCoolDown = swapi.DefaultBreakerConfig.CoolDown
+	cfg.SWAPI.CacheSize = swapi.DefaultCacheConfig.MaxEntries
+	cfg.SWAPI.CacheTTL = swapi.DefaultCacheConfig.DefaultTTL
+
+	return cfg
+}
+
+// newClient builds the client described by cfg: a snapshot client when a
+// snapshot directory is set, and otherwise the SWAPI client wrapped with
+// the circuit breaker, request coalescing and the response cache. Calls
+// that reach the upstream API and cache lookups are recorded in the API
+// metrics.
+func newClient(cfg config.SWAPI) (swapi.Client, error) {
+	if cfg.SnapshotDir != "" {
+		return swapi.NewSnapshotClient(cfg.SnapshotDir)
+	}
+
+	breakerConfig := swapi.DefaultBreakerConfig
+	breakerConfig.FailureThreshold = cfg.BreakerThreshold
+	breakerConfig.CoolDown = cfg.BreakerCoolDown
+
+	cacheConfig := swapi.DefaultCacheConfig
+	cacheConfig.MaxEntries = cfg.CacheSize
+	cacheConfig.DefaultTTL = cfg.CacheTTL
+
+	client := swapi.NewObserved(swapi.NewSWAPIClient(swapiOptions(cfg)...), api.ObserveUpstream)
+
+	cache := swapi.NewCache(swapi.NewCoalescer(swapi.NewCircuitBreaker(client, breakerConfig)), cacheConfig)
+	api.ObserveCache(cache.Stats)
+
+	return cache, nil
+}
+
+// swapiOptions applies the upstream settings of cfg: base URL, timeout,
+// User-Agent, maximum response size and retry attempts. Every attempt is
+// recorded in the upstream metrics.
+func swapiOptions(cfg config.SWAPI) []swapi.Option {
+	retryPolicy := swapi.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = cfg.RetryAttempts
 
-	if err := api.Run(); err != nil {
-		panic(err)
+	return []swapi.Option{
+		swapi.WithBaseURL(cfg.BaseURL),
+		swapi.WithTimeout(cfg.Timeout),
+		swapi.WithUserAgent(cfg.UserAgent),
+		swapi.WithMaxResponseSize(cfg.MaxResponseSize),
+		swapi.WithRetryPolicy(retryPolicy),
+		swapi.WithAttemptObserver(api.ObserveAttempt),
 	}
 }
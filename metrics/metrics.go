@@ -0,0 +1,237 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of latency histograms.
+var DefaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Registry holds metric families and writes them in the Prometheus text
+// exposition format.
+type Registry struct {
+	mu       sync.Mutex
+	families []*family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// family is a named metric with one series per combination of label values.
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+	read    func() float64
+}
+
+type series struct {
+	values []string
+	count  float64
+	sum    float64
+	counts []float64
+}
+
+// CounterVec is a counter partitioned by label values.
+type CounterVec struct {
+	registry *Registry
+	family   *family
+}
+
+// HistogramVec is a histogram partitioned by label values.
+type HistogramVec struct {
+	registry *Registry
+	family   *family
+}
+
+// Counter registers a counter named name with the given labels.
+func (r *Registry) Counter(name string, help string, labels ...string) *CounterVec {
+	return &CounterVec{registry: r, family: r.register(name, help, "counter", labels, nil)}
+}
+
+// CounterFunc registers a counter without labels whose value is read from
+// read every time the metrics are written, for counters kept elsewhere.
+func (r *Registry) CounterFunc(name string, help string, read func() float64) {
+	r.register(name, help, "counter", nil, nil).read = read
+}
+
+// Histogram registers a histogram named name with the given bucket upper
+// bounds, in increasing order, and labels.
+func (r *Registry) Histogram(name string, help string, buckets []float64, labels ...string) *HistogramVec {
+	return &HistogramVec{registry: r, family: r.register(name, help, "histogram", labels, buckets)}
+}
+
+func (r *Registry) register(name string, help string, kind string, labels []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f := &family{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: map[string]*series{}}
+	r.families = append(r.families, f)
+
+	return f
+}
+
+// Inc adds one to the series with the given label values.
+func (c *CounterVec) Inc(values ...string) {
+	c.registry.mu.Lock()
+	defer c.registry.mu.Unlock()
+
+	c.family.get(values).count++
+}
+
+// Observe records value in the series with the given label values.
+func (h *HistogramVec) Observe(value float64, values ...string) {
+	h.registry.mu.Lock()
+	defer h.registry.mu.Unlock()
+
+	s := h.family.get(values)
+	s.count++
+	s.sum += value
+
+	for i, bound := range h.family.buckets {
+		if value <= bound {
+			s.counts[i]++
+		}
+	}
+}
+
+func (f *family) get(values []string) *series {
+	if len(values) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s expects %d label values, got %d", f.name, len(f.labels), len(values)))
+	}
+
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+
+	if !ok {
+		s = &series{values: values, counts: make([]float64, len(f.buckets))}
+		f.series[key] = s
+	}
+
+	return s
+}
+
+// WriteTo writes every metric to w in the Prometheus text format, with the
+// series of each family ordered by label values.
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	out := &countingWriter{w: bufio.NewWriter(w)}
+
+	for _, f := range r.families {
+		fmt.Fprintf(out, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+		fmt.Fprintf(out, "# TYPE %s %s\n", f.name, f.kind)
+
+		if f.read != nil {
+			fmt.Fprintf(out, "%s %s\n", f.name, formatValue(f.read()))
+			continue
+		}
+
+		keys := make([]string, 0, len(f.series))
+
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			s := f.series[key]
+
+			if f.kind == "counter" {
+				fmt.Fprintf(out, "%s%s %s\n", f.name, labelSet(f.labels, s.values, "", ""), formatValue(s.count))
+				continue
+			}
+
+			for i, bound := range f.buckets {
+				fmt.Fprintf(out, "%s_bucket%s %s\n", f.name, labelSet(f.labels, s.values, "le", formatValue(bound)), formatValue(s.counts[i]))
+			}
+
+			fmt.Fprintf(out, "%s_bucket%s %s\n", f.name, labelSet(f.labels, s.values, "le", "+Inf"), formatValue(s.count))
+			fmt.Fprintf(out, "%s_sum%s %s\n", f.name, labelSet(f.labels, s.values, "", ""), formatValue(s.sum))
+			fmt.Fprintf(out, "%s_count%s %s\n", f.name, labelSet(f.labels, s.values, "", ""), formatValue(s.count))
+		}
+	}
+
+	if err := out.w.Flush(); err != nil {
+		return out.n, err
+	}
+
+	return out.n, out.err
+}
+
+// Handler serves the metrics of r.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		r.WriteTo(rw)
+	})
+}
+
+func labelSet(names []string, values []string, extraName string, extraValue string) string {
+	pairs := []string{}
+
+	for i, name := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeLabel(values[i])))
+	}
+
+	if extraName != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, extraName, extraValue))
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+var helpEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+
+func escapeLabel(value string) string {
+	return labelEscaper.Replace(value)
+}
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+
+	if err != nil && cw.err == nil {
+		cw.err = err
+	}
+
+	return n, err
+}
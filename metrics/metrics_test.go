@@ -0,0 +1,88 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryWritesCounters(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.Counter("requests_total", "Requests served.", "route", "status")
+
+	requests.Inc("/b", "200")
+	requests.Inc("/a", "404")
+	requests.Inc("/b", "200")
+	requests.Inc(`/"quoted"\`, "500")
+
+	out := &bytes.Buffer{}
+	_, err := registry.WriteTo(out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP requests_total Requests served.
+# TYPE requests_total counter
+requests_total{route="/\"quoted\"\\",status="500"} 1
+requests_total{route="/a",status="404"} 1
+requests_total{route="/b",status="200"} 2
+`, out.String())
+}
+
+func TestRegistryWritesCounterFuncs(t *testing.T) {
+	registry := NewRegistry()
+	hits := 0.0
+	registry.CounterFunc("hits_total", "Cache hits.", func() float64 { return hits })
+
+	hits = 3
+
+	out := &bytes.Buffer{}
+	_, err := registry.WriteTo(out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP hits_total Cache hits.
+# TYPE hits_total counter
+hits_total 3
+`, out.String())
+}
+
+func TestRegistryWritesHistograms(t *testing.T) {
+	registry := NewRegistry()
+	latency := registry.Histogram("latency_seconds", "Latency.", []float64{0.1, 1}, "method")
+
+	latency.Observe(0.05, "GetStarship")
+	latency.Observe(0.5, "GetStarship")
+	latency.Observe(3, "GetStarship")
+
+	out := &bytes.Buffer{}
+	_, err := registry.WriteTo(out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `# HELP latency_seconds Latency.
+# TYPE latency_seconds histogram
+latency_seconds_bucket{method="GetStarship",le="0.1"} 1
+latency_seconds_bucket{method="GetStarship",le="1"} 2
+latency_seconds_bucket{method="GetStarship",le="+Inf"} 3
+latency_seconds_sum{method="GetStarship"} 3.55
+latency_seconds_count{method="GetStarship"} 3
+`, out.String())
+}
+
+func TestRegistryRejectsWrongLabelCount(t *testing.T) {
+	registry := NewRegistry()
+	requests := registry.Counter("requests_total", "Requests served.", "route")
+
+	assert.Panics(t, func() { requests.Inc("/a", "200") })
+}
+
+func TestRegistryHandler(t *testing.T) {
+	registry := NewRegistry()
+	registry.Counter("requests_total", "Requests served.").Inc()
+
+	response := httptest.NewRecorder()
+	registry.Handler().ServeHTTP(response, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, "text/plain; version=0.0.4; charset=utf-8", response.Header().Get("Content-Type"))
+	assert.Contains(t, response.Body.String(), "requests_total 1\n")
+}
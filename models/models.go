@@ -1,44 +1,150 @@
 package models
 
+import "time"
+
 type Starship struct {
+	ID                   int       `json:"id"`
+	Name                 string    `json:"name"`
+	Model                string    `json:"model"`
+	Class                string    `json:"starship_class"`
+	Manufacturer         string    `json:"manufacturer"`
+	CostInCredits        string    `json:"cost_in_credits"`
+	Length               string    `json:"length"`
+	Crew                 string    `json:"crew"`
+	Passengers           string    `json:"passengers"`
+	MaxAtmospheringSpeed string    `json:"max_atmosphering_speed"`
+	HyperdriveRating     string    `json:"hyperdrive_rating"`
+	MGLT                 string    `json:"MGLT"`
+	CargoCapacity        string    `json:"cargo_capacity"`
+	Consumables          string    `json:"consumables"`
+	Films                []string  `json:"films"`
+	Pilots               []string  `json:"pilots"`
+	Created              time.Time `json:"created"`
+	Edited               time.Time `json:"edited"`
+	URL                  string    `json:"url"`
+}
+
+type Starships struct {
+	Count    int        `json:"count"`
+	Next     *string    `json:"next"`
+	Previous *string    `json:"previous"`
+	Results  []Starship `json:"results"`
+}
+
+type People struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	BirthYear string    `json:"birth_year"`
+	EyeColor  string    `json:"eye_color"`
+	Gender    string    `json:"gender"`
+	HairColor string    `json:"hair_color"`
+	Height    string    `json:"height"`
+	Mass      string    `json:"mass"`
+	SkinColor string    `json:"skin_color"`
+	Homeworld string    `json:"homeworld"`
+	Films     []string  `json:"films"`
+	Species   []string  `json:"species"`
+	Starships []string  `json:"starships"`
+	Vehicles  []string  `json:"vehicles"`
+	Created   time.Time `json:"created"`
+	Edited    time.Time `json:"edited"`
+	URL       string    `json:"url"`
+}
+
+type PeopleList struct {
+	Count    int      `json:"count"`
+	Next     *string  `json:"next"`
+	Previous *string  `json:"previous"`
+	Results  []People `json:"results"`
+}
+
+type Film struct {
+	Title        string   `json:"title"`
+	EpisodeID    int      `json:"episode_id"`
+	OpeningCrawl string   `json:"opening_crawl"`
+	Director     string   `json:"director"`
+	Producer     string   `json:"producer"`
+	ReleaseDate  string   `json:"release_date"`
+	Characters   []string `json:"characters"`
+	Planets      []string `json:"planets"`
+	Starships    []string `json:"starships"`
+	Vehicles     []string `json:"vehicles"`
+	Species      []string `json:"species"`
+	URL          string   `json:"url,omitempty"`
+}
+
+type Films struct {
+	Count    int     `json:"count"`
+	Next     *string `json:"next"`
+	Previous *string `json:"previous"`
+	Results  []Film  `json:"results"`
+}
+
+type Planet struct {
+	Name           string   `json:"name"`
+	RotationPeriod string   `json:"rotation_period"`
+	OrbitalPeriod  string   `json:"orbital_period"`
+	Diameter       string   `json:"diameter"`
+	Climate        string   `json:"climate"`
+	Gravity        string   `json:"gravity"`
+	Terrain        string   `json:"terrain"`
+	SurfaceWater   string   `json:"surface_water"`
+	Population     string   `json:"population"`
+	Residents      []string `json:"residents"`
+	Films          []string `json:"films"`
+	URL            string   `json:"url,omitempty"`
+}
+
+type Planets struct {
+	Count    int      `json:"count"`
+	Next     *string  `json:"next"`
+	Previous *string  `json:"previous"`
+	Results  []Planet `json:"results"`
+}
+
+type Species struct {
+	Name            string   `json:"name"`
+	Classification  string   `json:"classification"`
+	Designation     string   `json:"designation"`
+	AverageHeight   string   `json:"average_height"`
+	AverageLifespan string   `json:"average_lifespan"`
+	EyeColors       string   `json:"eye_colors"`
+	HairColors      string   `json:"hair_colors"`
+	SkinColors      string   `json:"skin_colors"`
+	Language        string   `json:"language"`
+	Homeworld       string   `json:"homeworld"`
+	People          []string `json:"people"`
+	Films           []string `json:"films"`
+	URL             string   `json:"url,omitempty"`
+}
+
+type SpeciesList struct {
+	Count    int       `json:"count"`
+	Next     *string   `json:"next"`
+	Previous *string   `json:"previous"`
+	Results  []Species `json:"results"`
+}
+
+type Vehicle struct {
 	Name                 string   `json:"name"`
 	Model                string   `json:"model"`
-	Class                string   `json:"starship_class"`
+	Class                string   `json:"vehicle_class"`
 	Manufacturer         string   `json:"manufacturer"`
 	CostInCredits        string   `json:"cost_in_credits"`
 	Length               string   `json:"length"`
 	Crew                 string   `json:"crew"`
 	Passengers           string   `json:"passengers"`
 	MaxAtmospheringSpeed string   `json:"max_atmosphering_speed"`
-	HyperdriveRating     string   `json:"hyperdrive_rating"`
-	MGLT                 string   `json:"MGLT"`
 	CargoCapacity        string   `json:"cargo_capacity"`
 	Consumables          string   `json:"consumables"`
 	Films                []string `json:"films"`
 	Pilots               []string `json:"pilots"`
+	URL                  string   `json:"url,omitempty"`
 }
 
-type Starships struct {
-	Count   int        `json:"count"`
-	Results []Starship `json:"results"`
-}
-
-type People struct {
-	Name      string   `json:"name"`
-	BirthYear string   `json:"birth_year"`
-	EyeColor  string   `json:"eye_color"`
-	Gender    string   `json:"gender"`
-	HairColor string   `json:"hair_color"`
-	Height    string   `json:"height"`
-	Mass      string   `json:"mass"`
-	SkinColor string   `json:"skin_color"`
-	Homeworld string   `json:"homeworld"`
-	Films     []string `json:"films"`
-	Species   []string `json:"species"`
-	Starships []string `json:"starships"`
-}
-
-type PeopleList struct {
-	Count   int      `json:"count"`
-	Results []People `json:"results"`
+type Vehicles struct {
+	Count    int       `json:"count"`
+	Next     *string   `json:"next"`
+	Previous *string   `json:"previous"`
+	Results  []Vehicle `json:"results"`
 }
@@ -0,0 +1,215 @@
+package models
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Range is a numeric attribute given as an interval, like a crew of
+// "30-165". Single values have the same Min and Max.
+type Range struct {
+	Min int64 `json:"min"`
+	Max int64 `json:"max"`
+}
+
+// TypedStarship is the typed view of Starship. Numeric attributes are null
+// when SWAPI reports them as "unknown", "n/a" or another non numeric value.
+type TypedStarship struct {
+	ID                   int       `json:"id"`
+	Name                 string    `json:"name"`
+	Model                string    `json:"model"`
+	Class                string    `json:"starship_class"`
+	Manufacturer         string    `json:"manufacturer"`
+	CostInCredits        *int64    `json:"cost_in_credits"`
+	Length               *float64  `json:"length"`
+	Crew                 *Range    `json:"crew"`
+	Passengers           *Range    `json:"passengers"`
+	MaxAtmospheringSpeed *int64    `json:"max_atmosphering_speed"`
+	HyperdriveRating     *float64  `json:"hyperdrive_rating"`
+	MGLT                 *int64    `json:"MGLT"`
+	CargoCapacity        *int64    `json:"cargo_capacity"`
+	Consumables          string    `json:"consumables"`
+	Films                []string  `json:"films"`
+	Pilots               []string  `json:"pilots"`
+	Created              time.Time `json:"created"`
+	Edited               time.Time `json:"edited"`
+	URL                  string    `json:"url"`
+}
+
+type TypedStarships struct {
+	Count    int             `json:"count"`
+	Next     *string         `json:"next"`
+	Previous *string         `json:"previous"`
+	Results  []TypedStarship `json:"results"`
+}
+
+// TypedPeople is the typed view of People.
+type TypedPeople struct {
+	ID        int       `json:"id"`
+	Name      string    `json:"name"`
+	BirthYear string    `json:"birth_year"`
+	EyeColor  string    `json:"eye_color"`
+	Gender    string    `json:"gender"`
+	HairColor string    `json:"hair_color"`
+	Height    *int64    `json:"height"`
+	Mass      *float64  `json:"mass"`
+	SkinColor string    `json:"skin_color"`
+	Homeworld string    `json:"homeworld"`
+	Films     []string  `json:"films"`
+	Species   []string  `json:"species"`
+	Starships []string  `json:"starships"`
+	Vehicles  []string  `json:"vehicles"`
+	Created   time.Time `json:"created"`
+	Edited    time.Time `json:"edited"`
+	URL       string    `json:"url"`
+}
+
+type TypedPeopleList struct {
+	Count    int           `json:"count"`
+	Next     *string       `json:"next"`
+	Previous *string       `json:"previous"`
+	Results  []TypedPeople `json:"results"`
+}
+
+func (s Starship) Typed() TypedStarship {
+	return TypedStarship{
+		ID:                   s.ID,
+		Name:                 s.Name,
+		Model:                s.Model,
+		Class:                s.Class,
+		Manufacturer:         s.Manufacturer,
+		CostInCredits:        nullableInt(s.CostInCredits),
+		Length:               nullableFloat(s.Length),
+		Crew:                 nullableRange(s.Crew),
+		Passengers:           nullableRange(s.Passengers),
+		MaxAtmospheringSpeed: nullableInt(s.MaxAtmospheringSpeed),
+		HyperdriveRating:     nullableFloat(s.HyperdriveRating),
+		MGLT:                 nullableInt(s.MGLT),
+		CargoCapacity:        nullableInt(s.CargoCapacity),
+		Consumables:          s.Consumables,
+		Films:                s.Films,
+		Pilots:               s.Pilots,
+		Created:              s.Created,
+		Edited:               s.Edited,
+		URL:                  s.URL,
+	}
+}
+
+func (s Starships) Typed() TypedStarships {
+	results := make([]TypedStarship, len(s.Results))
+
+	for i, starship := range s.Results {
+		results[i] = starship.Typed()
+	}
+
+	return TypedStarships{Count: s.Count, Next: s.Next, Previous: s.Previous, Results: results}
+}
+
+func (p People) Typed() TypedPeople {
+	return TypedPeople{
+		ID:        p.ID,
+		Name:      p.Name,
+		BirthYear: p.BirthYear,
+		EyeColor:  p.EyeColor,
+		Gender:    p.Gender,
+		HairColor: p.HairColor,
+		Height:    nullableInt(p.Height),
+		Mass:      nullableFloat(p.Mass),
+		SkinColor: p.SkinColor,
+		Homeworld: p.Homeworld,
+		Films:     p.Films,
+		Species:   p.Species,
+		Starships: p.Starships,
+		Vehicles:  p.Vehicles,
+		Created:   p.Created,
+		Edited:    p.Edited,
+		URL:       p.URL,
+	}
+}
+
+func (p PeopleList) Typed() TypedPeopleList {
+	results := make([]TypedPeople, len(p.Results))
+
+	for i, people := range p.Results {
+		results[i] = people.Typed()
+	}
+
+	return TypedPeopleList{Count: p.Count, Next: p.Next, Previous: p.Previous, Results: results}
+}
+
+// ParseFloat reads a SWAPI numeric attribute such as "1,600", "78.2" or
+// "1000km". It reports false for values like "unknown" or "n/a".
+func ParseFloat(value string) (float64, bool) {
+	cleaned := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(value), ",", ""))
+	cleaned = strings.TrimSpace(strings.TrimRight(cleaned, "abcdefghijklmnopqrstuvwxyz"))
+
+	if cleaned == "" {
+		return 0, false
+	}
+
+	number, err := strconv.ParseFloat(cleaned, 64)
+
+	if err != nil {
+		return 0, false
+	}
+
+	return number, true
+}
+
+// ParseInt is ParseFloat for attributes holding whole numbers.
+func ParseInt(value string) (int64, bool) {
+	number, ok := ParseFloat(value)
+
+	if !ok || number != float64(int64(number)) {
+		return 0, false
+	}
+
+	return int64(number), true
+}
+
+// ParseRange reads either a single number or an interval like "30-165".
+func ParseRange(value string) (Range, bool) {
+	bounds := strings.SplitN(value, "-", 2)
+	min, ok := ParseInt(bounds[0])
+
+	if !ok {
+		return Range{}, false
+	}
+
+	if len(bounds) == 1 {
+		return Range{Min: min, Max: min}, true
+	}
+
+	max, ok := ParseInt(bounds[1])
+
+	if !ok || max < min {
+		return Range{}, false
+	}
+
+	return Range{Min: min, Max: max}, true
+}
+
+func nullableInt(value string) *int64 {
+	if number, ok := ParseInt(value); ok {
+		return &number
+	}
+
+	return nil
+}
+
+func nullableFloat(value string) *float64 {
+	if number, ok := ParseFloat(value); ok {
+		return &number
+	}
+
+	return nil
+}
+
+func nullableRange(value string) *Range {
+	if r, ok := ParseRange(value); ok {
+		return &r
+	}
+
+	return nil
+}
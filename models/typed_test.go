@@ -0,0 +1,57 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFloat(t *testing.T) {
+	cases := map[string]interface{}{
+		"1,600":   1600.0,
+		"78.2":    78.2,
+		"1000km":  1000.0,
+		" 36.8 ":  36.8,
+		"unknown": nil,
+		"n/a":     nil,
+		"":        nil,
+		"none":    nil,
+	}
+
+	for value, expected := range cases {
+		number, ok := ParseFloat(value)
+
+		if expected == nil {
+			assert.False(t, ok, value)
+			continue
+		}
+
+		assert.True(t, ok, value)
+		assert.Equal(t, expected, number, value)
+	}
+}
+
+func TestParseInt(t *testing.T) {
+	number, ok := ParseInt("47,060")
+	assert.True(t, ok)
+	assert.Equal(t, int64(47060), number)
+
+	_, ok = ParseInt("4.5")
+	assert.False(t, ok)
+}
+
+func TestParseRange(t *testing.T) {
+	r, ok := ParseRange("30-165")
+	assert.True(t, ok)
+	assert.Equal(t, Range{Min: 30, Max: 165}, r)
+
+	r, ok = ParseRange("1,000")
+	assert.True(t, ok)
+	assert.Equal(t, Range{Min: 1000, Max: 1000}, r)
+
+	_, ok = ParseRange("n/a")
+	assert.False(t, ok)
+
+	_, ok = ParseRange("165-30")
+	assert.False(t, ok)
+}
@@ -1,22 +1,152 @@
 package services
 
 import (
+	"context"
+	"fmt"
+	"sync"
+
 	"github.com/klasrak/go-meli-test-dojo/clients/swapi"
+	"github.com/klasrak/go-meli-test-dojo/errors"
 	"github.com/klasrak/go-meli-test-dojo/models"
 )
 
-func GetStarshipService(id int) (models.Starship, error) {
-	return swapi.Instance.GetStarship(id)
+func GetStarshipService(ctx context.Context, id int) (models.Starship, error) {
+	return swapi.Instance.GetStarship(ctx, id)
+}
+
+func GetStarshipsService(ctx context.Context, page int, search string) (models.Starships, error) {
+	return swapi.Instance.GetStarships(ctx, page, search)
+}
+
+func GetPeopleService(ctx context.Context, id int) (models.People, error) {
+	return swapi.Instance.GetPeople(ctx, id)
+}
+
+func GetPeopleListService(ctx context.Context, page int, search string) (models.PeopleList, error) {
+	return swapi.Instance.GetPeopleList(ctx, page, search)
+}
+
+func GetFilmService(ctx context.Context, id int) (models.Film, error) {
+	return swapi.Instance.GetFilm(ctx, id)
+}
+
+func GetFilmsService(ctx context.Context, page int, search string) (models.Films, error) {
+	return swapi.Instance.GetFilms(ctx, page, search)
+}
+
+func GetPlanetService(ctx context.Context, id int) (models.Planet, error) {
+	return swapi.Instance.GetPlanet(ctx, id)
+}
+
+func GetPlanetsService(ctx context.Context, page int, search string) (models.Planets, error) {
+	return swapi.Instance.GetPlanets(ctx, page, search)
+}
+
+// GetPeopleHomeworldService resolves the planet referenced by the
+// homeworld URL of the people with the given id.
+func GetPeopleHomeworldService(ctx context.Context, id int) (models.Planet, error) {
+	people, err := swapi.Instance.GetPeople(ctx, id)
+
+	if err != nil {
+		return models.Planet{}, err
+	}
+
+	planetID, err := swapi.ParseID(people.Homeworld)
+
+	if err != nil {
+		return models.Planet{}, errors.NewNotFound("homeworld of people", fmt.Sprintf("%d", id))
+	}
+
+	return swapi.Instance.GetPlanet(ctx, planetID)
 }
 
-func GetStarshipsService() (models.Starships, error) {
-	return swapi.Instance.GetStarships()
+func GetSpeciesService(ctx context.Context, id int) (models.Species, error) {
+	return swapi.Instance.GetSpecies(ctx, id)
 }
 
-func GetPeopleService(id int) (models.People, error) {
-	return swapi.Instance.GetPeople(id)
+func GetSpeciesListService(ctx context.Context, page int, search string) (models.SpeciesList, error) {
+	return swapi.Instance.GetSpeciesList(ctx, page, search)
 }
 
-func GetPeopleListService() (models.PeopleList, error) {
-	return swapi.Instance.GetPeopleList()
+func GetVehicleService(ctx context.Context, id int) (models.Vehicle, error) {
+	return swapi.Instance.GetVehicle(ctx, id)
+}
+
+func GetVehiclesService(ctx context.Context, page int, search string) (models.Vehicles, error) {
+	return swapi.Instance.GetVehicles(ctx, page, search)
+}
+
+// maxConcurrentLinks bounds how many links ResolveLinksService fetches at
+// the same time.
+const maxConcurrentLinks = 8
+
+// GetResourceService fetches a single resource by its SWAPI resource name.
+func GetResourceService(ctx context.Context, resource string, id int) (interface{}, error) {
+	switch resource {
+	case "starships":
+		return swapi.Instance.GetStarship(ctx, id)
+	case "people":
+		return swapi.Instance.GetPeople(ctx, id)
+	case "films":
+		return swapi.Instance.GetFilm(ctx, id)
+	case "planets":
+		return swapi.Instance.GetPlanet(ctx, id)
+	case "species":
+		return swapi.Instance.GetSpecies(ctx, id)
+	case "vehicles":
+		return swapi.Instance.GetVehicle(ctx, id)
+	default:
+		return nil, errors.NewNotFound(resource, fmt.Sprintf("%d", id))
+	}
+}
+
+// ResolveLinksService fetches the resources behind links concurrently.
+// Links that cannot be fetched are left out of the result instead of
+// failing the whole resolution.
+func ResolveLinksService(ctx context.Context, links []string) map[string]interface{} {
+	resolved := make(map[string]interface{}, len(links))
+
+	seen := make(map[string]bool, len(links))
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentLinks)
+	)
+
+	for _, link := range links {
+		if seen[link] {
+			continue
+		}
+
+		seen[link] = true
+
+		resource, id, err := swapi.ParseResource(link)
+
+		if err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(link string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			result, err := GetResourceService(ctx, resource, id)
+
+			if err != nil {
+				return
+			}
+
+			mu.Lock()
+			resolved[link] = result
+			mu.Unlock()
+		}(link)
+	}
+
+	wg.Wait()
+
+	return resolved
 }
@@ -0,0 +1,279 @@
+package snapshots
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/clients/swapi"
+	"github.com/klasrak/go-meli-test-dojo/utils"
+)
+
+const (
+	ManifestFile = "manifest.json"
+	progressFile = ".progress.json"
+	versionTime  = "20060102T150405Z"
+)
+
+var now = time.Now
+
+// Manifest describes a complete snapshot. It is written last, so a version
+// directory without it is an interrupted sync.
+type Manifest struct {
+	Version   string                      `json:"version"`
+	CreatedAt time.Time                   `json:"created_at"`
+	Resources map[string]ResourceManifest `json:"resources"`
+}
+
+// ResourceManifest holds the number of files of a resource and the SHA-256
+// checksum of each of them, keyed by file name.
+type ResourceManifest struct {
+	Count     int               `json:"count"`
+	Checksums map[string]string `json:"checksums"`
+}
+
+// progress records the last page stored for each resource so an interrupted
+// sync can resume where it stopped.
+type progress struct {
+	Pages map[string]int  `json:"pages"`
+	Done  map[string]bool `json:"done"`
+}
+
+// Pager fetches pages of a resource list as the upstream API sent them.
+type Pager interface {
+	GetRawPage(ctx context.Context, resource string, page int) (swapi.RawPage, error)
+}
+
+// resources lists every resource Sync knows how to crawl.
+var resources = map[string]bool{
+	"starships": true,
+	"people":    true,
+	"films":     true,
+	"planets":   true,
+	"species":   true,
+	"vehicles":  true,
+}
+
+// Resources lists every resource Sync knows how to crawl.
+func Resources() []string {
+	names := make([]string, 0, len(resources))
+
+	for name := range resources {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Sync crawls every page of names (all resources when empty) through client
+// into a versioned directory under root and returns its path. Every item is
+// stored as the upstream payload. When the newest version under root is
+// unfinished, Sync resumes it instead of starting over.
+func Sync(ctx context.Context, client Pager, root string, names []string) (string, Manifest, error) {
+	if len(names) == 0 {
+		names = Resources()
+	}
+
+	for _, name := range names {
+		if !resources[name] {
+			return "", Manifest{}, fmt.Errorf("unknown resource: %s", name)
+		}
+	}
+
+	dir, err := versionDir(root)
+
+	if err != nil {
+		return "", Manifest{}, err
+	}
+
+	state, err := readProgress(dir)
+
+	if err != nil {
+		return dir, Manifest{}, err
+	}
+
+	for _, name := range names {
+		if err := crawl(ctx, client, dir, name, state); err != nil {
+			return dir, Manifest{}, err
+		}
+	}
+
+	manifest, err := writeManifest(dir, names)
+
+	if err != nil {
+		return dir, Manifest{}, err
+	}
+
+	return dir, manifest, os.Remove(filepath.Join(dir, progressFile))
+}
+
+// crawl stores the pages of resource that were not stored yet, saving the
+// progress after each of them.
+func crawl(ctx context.Context, client Pager, dir string, name string, state *progress) error {
+	if state.Done[name] {
+		return nil
+	}
+
+	resourceDir := filepath.Join(dir, name)
+
+	if err := os.MkdirAll(resourceDir, 0o755); err != nil {
+		return err
+	}
+
+	for page := state.Pages[name] + 1; ; page++ {
+		list, err := client.GetRawPage(ctx, name, page)
+
+		if err != nil {
+			return fmt.Errorf("%s page %d: %w", name, page, err)
+		}
+
+		for _, raw := range list.Results {
+			var item struct {
+				URL string `json:"url"`
+			}
+
+			if err := json.Unmarshal(raw, &item); err != nil {
+				return fmt.Errorf("%s page %d: %w", name, page, err)
+			}
+
+			id, err := swapi.ParseID(item.URL)
+
+			if err != nil {
+				return fmt.Errorf("%s page %d: %w", name, page, err)
+			}
+
+			if err := writeFileAtomic(filepath.Join(resourceDir, fmt.Sprintf("%d.json", id)), raw); err != nil {
+				return err
+			}
+		}
+
+		more := list.Next != nil
+		state.Pages[name] = page
+		state.Done[name] = !more
+
+		if err := writeFileAtomic(filepath.Join(dir, progressFile), utils.ToJSON(state)); err != nil {
+			return err
+		}
+
+		if !more {
+			return nil
+		}
+	}
+}
+
+// versionDir returns the newest unfinished version under root, or creates a
+// new one named after the current time.
+func versionDir(root string) (string, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return "", err
+	}
+
+	entries, err := ioutil.ReadDir(root)
+
+	if err != nil {
+		return "", err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		if !entries[i].IsDir() {
+			continue
+		}
+
+		dir := filepath.Join(root, entries[i].Name())
+
+		if _, err := os.Stat(filepath.Join(dir, ManifestFile)); os.IsNotExist(err) {
+			return dir, nil
+		}
+
+		break
+	}
+
+	dir := filepath.Join(root, now().UTC().Format(versionTime))
+
+	return dir, os.MkdirAll(dir, 0o755)
+}
+
+func readProgress(dir string) (*progress, error) {
+	state := &progress{Pages: map[string]int{}, Done: map[string]bool{}}
+	body, err := ioutil.ReadFile(filepath.Join(dir, progressFile))
+
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(body, state); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+func writeManifest(dir string, names []string) (Manifest, error) {
+	manifest := Manifest{
+		Version:   filepath.Base(dir),
+		CreatedAt: now().UTC(),
+		Resources: map[string]ResourceManifest{},
+	}
+
+	for _, name := range names {
+		files, err := ioutil.ReadDir(filepath.Join(dir, name))
+
+		if err != nil {
+			return Manifest{}, err
+		}
+
+		resource := ResourceManifest{Checksums: map[string]string{}}
+
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+				continue
+			}
+
+			body, err := ioutil.ReadFile(filepath.Join(dir, name, file.Name()))
+
+			if err != nil {
+				return Manifest{}, err
+			}
+
+			sum := sha256.Sum256(body)
+			resource.Checksums[file.Name()] = hex.EncodeToString(sum[:])
+		}
+
+		resource.Count = len(resource.Checksums)
+		manifest.Resources[name] = resource
+	}
+
+	body, err := json.MarshalIndent(manifest, "", "  ")
+
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return manifest, writeFileAtomic(filepath.Join(dir, ManifestFile), body)
+}
+
+// writeFileAtomic writes through a temporary file so an interruption never
+// leaves a truncated file behind.
+func writeFileAtomic(path string, body []byte) error {
+	tmp := path + ".tmp"
+
+	if err := ioutil.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,107 @@
+package snapshots
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/klasrak/go-meli-test-dojo/clients/swapi"
+	"github.com/stretchr/testify/assert"
+)
+
+// newUpstream serves 3 starships, 2 per page, and a single person, as raw
+// upstream JSON. Page 2 of starships fails while failing is set.
+func newUpstream(t *testing.T, failing *bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+
+		switch {
+		case r.URL.Path == "/people/":
+			fmt.Fprintf(rw, `{"count":1,"next":null,"previous":null,"results":[{"name":"Luke Skywalker","url":"https://swapi.dev/api/people/1/"}]}`)
+		case r.URL.Path == "/starships/" && page == "1":
+			fmt.Fprintf(rw, `{"count":3,"next":"https://swapi.dev/api/starships/?page=2","previous":null,"results":[%s,%s]}`, starship(1), starship(2))
+		case r.URL.Path == "/starships/" && page == "2" && !*failing:
+			fmt.Fprintf(rw, `{"count":3,"next":null,"previous":"https://swapi.dev/api/starships/?page=1","results":[%s]}`, starship(3))
+		case r.URL.Path == "/starships/" && page == "2":
+			rw.WriteHeader(http.StatusInternalServerError)
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func starship(id int) string {
+	return fmt.Sprintf(`{"name":"starship %d","created":"2014-12-10T14:20:33.369000Z","edited":"2014-12-20T21:23:49.867000Z","url":"https://swapi.dev/api/starships/%d/"}`, id, id)
+}
+
+func newClient(server *httptest.Server) Pager {
+	return swapi.NewSWAPIClient(swapi.WithBaseURL(server.URL), swapi.WithRetryPolicy(swapi.RetryPolicy{MaxAttempts: 1}))
+}
+
+func TestSync(t *testing.T) {
+	now = func() time.Time { return time.Date(2022, 5, 4, 12, 0, 0, 0, time.UTC) }
+	defer func() { now = time.Now }()
+
+	failing := false
+	server := newUpstream(t, &failing)
+	defer server.Close()
+
+	root := t.TempDir()
+	dir, manifest, err := Sync(context.Background(), newClient(server), root, []string{"starships", "people"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(root, "20220504T120000Z"), dir)
+	assert.Equal(t, "20220504T120000Z", manifest.Version)
+	assert.Equal(t, 3, manifest.Resources["starships"].Count)
+	assert.Equal(t, 1, manifest.Resources["people"].Count)
+	assert.Len(t, manifest.Resources["starships"].Checksums["3.json"], 64)
+
+	assert.FileExists(t, filepath.Join(dir, ManifestFile))
+	assert.NoFileExists(t, filepath.Join(dir, progressFile))
+
+	client, err := swapi.NewSnapshotClient(dir)
+	assert.NoError(t, err)
+
+	ship, err := client.GetStarship(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, "starship 3", ship.Name)
+
+	body, err := os.ReadFile(filepath.Join(dir, "starships", "3.json"))
+	assert.NoError(t, err)
+	assert.Equal(t, starship(3), string(body))
+}
+
+func TestSyncResumes(t *testing.T) {
+	failing := true
+	server := newUpstream(t, &failing)
+	defer server.Close()
+
+	root := t.TempDir()
+	dir, _, err := Sync(context.Background(), newClient(server), root, []string{"starships", "people"})
+
+	assert.Error(t, err)
+	assert.FileExists(t, filepath.Join(dir, "starships", "1.json"))
+	assert.NoFileExists(t, filepath.Join(dir, ManifestFile))
+
+	failing = false
+	resumed, manifest, err := Sync(context.Background(), newClient(server), root, []string{"starships", "people"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, dir, resumed)
+	assert.Equal(t, 3, manifest.Resources["starships"].Count)
+
+	entries, err := os.ReadDir(root)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestSyncUnknownResource(t *testing.T) {
+	_, _, err := Sync(context.Background(), swapi.NewSWAPIClient(), t.TempDir(), []string{"droids"})
+
+	assert.Error(t, err)
+}
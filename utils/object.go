@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Object is a JSON object that keeps the order of its keys, so responses can
+// be reshaped without reordering the fields of the models.
+type Object struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+func NewObject() *Object {
+	return &Object{values: map[string]json.RawMessage{}}
+}
+
+// ToObject encodes v, which must encode to a JSON object, into an Object.
+func ToObject(v interface{}) (*Object, error) {
+	data, err := json.Marshal(v)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseObject(data)
+}
+
+// ParseObject decodes a JSON object keeping its keys in order.
+func ParseObject(data []byte) (*Object, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+
+	o := NewObject()
+
+	for decoder.More() {
+		token, err := decoder.Token()
+
+		if err != nil {
+			return nil, err
+		}
+
+		var value json.RawMessage
+
+		if err := decoder.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		o.set(token.(string), value)
+	}
+
+	return o, nil
+}
+
+func (o *Object) Keys() []string {
+	return o.keys
+}
+
+func (o *Object) Get(key string) (json.RawMessage, bool) {
+	value, ok := o.values[key]
+	return value, ok
+}
+
+// Set encodes value under key. New keys are appended after the existing ones.
+func (o *Object) Set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+
+	if err != nil {
+		return err
+	}
+
+	o.set(key, data)
+
+	return nil
+}
+
+func (o *Object) Delete(key string) {
+	if _, ok := o.values[key]; !ok {
+		return
+	}
+
+	delete(o.values, key)
+
+	for i, k := range o.keys {
+		if k == key {
+			o.keys = append(o.keys[:i], o.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, key := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(key)
+
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(o.values[key])
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+func (o *Object) set(key string, value json.RawMessage) {
+	if _, ok := o.values[key]; !ok {
+		o.keys = append(o.keys, key)
+	}
+
+	o.values[key] = value
+}